@@ -3,11 +3,15 @@ package main
 import (
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/integrii/flaggy"
@@ -16,7 +20,11 @@ import (
 
 	"burp-cli/modules/commander"
 	"burp-cli/modules/configure"
+	"burp-cli/modules/har"
+	"burp-cli/modules/hooks"
+	"burp-cli/modules/importer"
 	"burp-cli/modules/nmap"
+	"burp-cli/modules/openapi"
 	"burp-cli/modules/reporter"
 	"burp-cli/modules/scanner"
 	"burp-cli/modules/scheduler"
@@ -27,6 +35,7 @@ var yellow = color.New(color.Bold, color.FgYellow).SprintfFunc()
 var red = color.New(color.Bold, color.FgRed).SprintfFunc()
 var cyan = color.New(color.Bold, color.FgCyan).SprintfFunc()
 var green = color.New(color.Bold, color.FgGreen).SprintfFunc()
+var magenta = color.New(color.Bold, color.FgMagenta).SprintfFunc()
 
 var VERSION = `1.2.0`
 
@@ -63,6 +72,35 @@ var reportInput, reportOutput, reportFormat string
 // v1.2.1: Added scan listing and bulk export features
 var listScans, listAndExportAll, importFromBurp bool
 var clearOldScans int
+// v1.3.0: Added pre- and post-scan hook commands
+var preScanHook, postScanHook string
+var hookTimeout int
+// v1.3.0: Added scope import from Burp project options and bug-bounty exports
+var scopeFile string
+// v1.3.0: Added OpenAPI/Swagger seeded scans
+var openapiSpec, openapiServer string
+// v1.3.0: Added HAR seeded scans
+var harFile, harHosts string
+var harScope, harKeepStatic bool
+// v1.3.0: Added httpx/naabu/masscan import filters for -sl
+var matchStatus, matchTitle string
+// v1.3.0: Added CIDR/IP range expansion with port lists and live pre-probe
+var cidrTargets, portList string
+var probeTargets bool
+var probeTimeout, probeThreads, maxTargets int
+// v1.3.0: Added scan metadata recorded in the scan history
+var scanTags, scanProject, scanNotes string
+// v1.3.0: Added filters, sorting, pagination and output formats for -L
+var listStatus, listSince, listUntil, listURL, listConfig, listInstance, listSort, listOutput string
+var listLimit, listPage int
+// v1.3.0: Added scan discovery tuning for Burp synchronization
+var syncMisses, syncThreads int
+
+// defaultPageSize is used when --page is given without --limit
+const defaultPageSize = 50
+
+// syncClient is shared by the concurrent sync probes
+var syncClient = &http.Client{Timeout: 10 * time.Second}
 
 func init() {
 	flaggy.SetName("burp-cli")
@@ -73,13 +111,19 @@ USAGE EXAMPLES:
   Scanning:
     burp-cli -s "https://example.com" -a                    # Single URL scan with auto-export
     burp-cli -sl urls.txt -a                                # Scan multiple URLs
+    burp-cli -sl httpx.jsonl --match-status 200 -a          # Scan httpx/naabu/masscan output
+    burp-cli --cidr 10.20.0.0/24 --ports 80,443,8080-8090 --probe  # Scan live web services in a range
     burp-cli -sn nmap.xml -a                                # Scan from Nmap XML
+    burp-cli --openapi api.yaml -a                          # Seed scan from OpenAPI spec
+    burp-cli -sh session.har --har-scope -a                 # Seed scan from HAR capture
     burp-cli -s "https://example.com" -U admin -P pass -a   # Authenticated scan
+    burp-cli -s "https://example.com" -a --post-scan-hook ./upload.sh  # Run hook after scan
 
   Configuration:
     burp-cli -lc                                            # List available configs
     burp-cli -s "https://example.com" -cn 3 -a              # Use config by number
     burp-cli -s "https://example.com" -bc "SQL Injection"   # Use Burp config
+    burp-cli -s "https://example.com" --scope-file scope.csv  # Scope from Burp/H1/Bugcrowd export
 
   Scan Results:
     burp-cli -S 8 -M                                        # Get scan metrics
@@ -95,13 +139,24 @@ USAGE EXAMPLES:
     burp-cli -L                                             # List all scans (auto-syncs with Burp)
     burp-cli -LA                                            # List and export all (auto-syncs first)
     burp-cli --import-from-burp -L                          # Verbose import from Burp and list
+    burp-cli -L --status failed --url "*.corp.example" --since 7d  # Filter the scan list
+    burp-cli -L --sort started:desc --limit 20 --output wide  # Newest 20 scans with full URLs
+    burp-cli -L --output csv > scans.csv                    # Export the scan list as CSV or JSON
     burp-cli --clear-old-scans 30                           # Clear scans older than 30 days
+    burp-cli scans retention set --keep-last 5 --keep-days 90  # Retention policy for history and exports
+    burp-cli scans prune --dry-run                          # Preview expired scans, then archive them
+    burp-cli scans annotate 3 --tag prod --add-note "retest" # Edit tags and notes of a scan
+    burp-cli scans show 3                                   # Show metadata, final metrics and request
+    burp-cli scans metrics 3 --history                      # Sparklines of the sampled scan metrics
+    burp-cli scans export -o alice.json                     # Bundle scans to share with the team
+    burp-cli scans import alice.json bob.json               # Merge teammates' bundles into the history
     
     Note: -L and -LA automatically sync with Burp API if available
           No need to manually import or clear history!
 
   Scheduler:
     burp-cli schedule create daily --time 21:00 --url "https://example.com" --auto-export
+    burp-cli schedule create cron --cron "30 2 * * mon-fri" --url "https://example.com"
     burp-cli schedule list                                  # List all schedules
     burp-cli schedule daemon --foreground                   # Run scheduler daemon
 
@@ -132,8 +187,22 @@ Version Info: burp-cli -V  or  burp-cli --version
 	flaggy.String(&scan, "s", "scan", "URLs to scan")
 	flaggy.String(&scan_id, "S", "scan-id", "Scanned URL identifier")
 
-	flaggy.String(&nmapScan, "sn", "scan-nmap", "Nmap xml file to scan")
-	flaggy.String(&scanList, "sl", "scan-list", "File with hosts/Ip's to scan")
+	flaggy.String(&nmapScan, "sn", "scan-nmap", "Nmap output to scan (XML, grepable -oG or JSON)")
+	flaggy.String(&scanList, "sl", "scan-list", "File with hosts/Ip's to scan (plain list, httpx JSONL, naabu JSON or masscan XML/JSON)")
+	flaggy.String(&matchStatus, "", "match-status", "Only scan httpx results with these status codes (e.g., 200,301-302)")
+	flaggy.String(&matchTitle, "", "match-title", "Only scan httpx results whose title matches this regex")
+	flaggy.String(&cidrTargets, "", "cidr", "Space-separated CIDRs or IP ranges to scan, optionally with ports (e.g., \"10.20.0.0/24 10.0.0.1-50:8443\")")
+	flaggy.String(&portList, "", "ports", "Ports for CIDR/range targets without their own list (default: 80,443)")
+	flaggy.Bool(&probeTargets, "", "probe", "TCP/TLS pre-probe expanded targets and only scan live web services")
+	flaggy.Int(&probeTimeout, "", "probe-timeout", "Pre-probe connect timeout in milliseconds (default: 1500)")
+	flaggy.Int(&probeThreads, "", "probe-threads", "Concurrent pre-probe connections (default: 100)")
+	flaggy.Int(&maxTargets, "", "max-targets", "Maximum unprobed CIDR/range candidate URLs to scan (default: 1024)")
+	flaggy.String(&openapiSpec, "", "openapi", "OpenAPI 2/3 (Swagger) spec in YAML or JSON; every path and method becomes a seed URL")
+	flaggy.String(&openapiServer, "", "openapi-server", "Base URL overriding the servers declared in the OpenAPI spec")
+	flaggy.String(&harFile, "sh", "scan-har", "HAR capture whose unique request URLs seed a single scan")
+	flaggy.String(&harHosts, "", "har-hosts", "Comma-separated hosts to keep from the HAR capture (supports *.example.com)")
+	flaggy.Bool(&harScope, "", "har-scope", "Limit the scan scope to the hosts seen in the HAR capture")
+	flaggy.Bool(&harKeepStatic, "", "har-keep-static", "Keep static assets (scripts, styles, images, fonts) from the HAR capture")
 
 	flaggy.Bool(&metrics, "M", "metrics", "Provides metrics for a given task")
 	flaggy.String(&description, "D", "description", "Provides description for a given issue")
@@ -147,6 +216,8 @@ Version Info: burp-cli -V  or  burp-cli --version
 	flaggy.String(&scanConfig, "sc", "scan-config", "Scan configuration name (e.g., 'Crawl and Audit - Fast', 'Audit only - Fast')")
 	flaggy.String(&scopeInclude, "si", "scope-include", "Comma-separated list of URLs/patterns to include in scope")
 	flaggy.String(&scopeExclude, "se", "scope-exclude", "Comma-separated list of URLs/patterns to exclude from scope")
+	// v1.3.0: Scope import from Burp project options JSON or HackerOne/Bugcrowd CSV
+	flaggy.String(&scopeFile, "", "scope-file", "Burp project options JSON or HackerOne/Bugcrowd CSV scope export (includes exclusions)")
 	flaggy.String(&protocolOption, "po", "protocol-option", "Protocol option: 'httpAndHttps' or 'specified' (default: httpAndHttps)")
 	// v1.1.4: Custom configuration file support
 	flaggy.String(&customConfigFile, "cf", "config-file", "Path to custom Burp configuration JSON file (exported from Burp Suite)")
@@ -161,6 +232,10 @@ Version Info: burp-cli -V  or  burp-cli --version
 	flaggy.String(&callbackURL, "cb", "callback", "Callback URL to receive scan completion notifications")
 	flaggy.Bool(&advancedScope, "as", "advanced-scope", "Use advanced scope with protocol/port/file specifications")
 	flaggy.String(&recordedLoginScript, "rls", "recorded-login", "Path to recorded login script file")
+	// v1.3.0: Scan metadata flags
+	flaggy.String(&scanTags, "", "tags", "Comma-separated tags stored with the scan in the scan history (with -L: required tags)")
+	flaggy.String(&scanProject, "", "project", "Project or engagement name stored with the scan (with -L: project glob)")
+	flaggy.String(&scanNotes, "", "notes", "Free-text notes stored with the scan")
 
 	flaggy.String(&key, "k", "key", "Api Key")
 	// Version flag removed - handled before flaggy.Parse() in main()
@@ -173,8 +248,26 @@ Version Info: burp-cli -V  or  burp-cli --version
 	// v1.2.1: Scan listing and bulk export flags
 	flaggy.Bool(&listScans, "L", "list-scans", "List all tracked scans with their URLs and status")
 	flaggy.Bool(&listAndExportAll, "LA", "list-and-export-all", "List all scans and export HTML reports for each")
-	flaggy.Bool(&importFromBurp, "", "import-from-burp", "Import existing scans from Burp API (scans ID 1-50)")
+	flaggy.Bool(&importFromBurp, "", "import-from-burp", "Import existing scans from Burp API (rescans every ID from 1)")
 	flaggy.Int(&clearOldScans, "", "clear-old-scans", "Clear scan records older than specified days (e.g., --clear-old-scans 30)")
+	// v1.3.0: Scan listing filters and output; --tags and --project also filter -L
+	flaggy.String(&listStatus, "", "status", "With -L: comma-separated statuses to list (e.g., failed,cancelled)")
+	flaggy.String(&listSince, "", "since", "With -L: scans started on or after a date (YYYY-MM-DD, RFC 3339) or age (e.g., 7d, 36h)")
+	flaggy.String(&listUntil, "", "until", "With -L: scans started before a date or age")
+	flaggy.String(&listURL, "", "url", "With -L: glob matched against the scan URL or host (e.g., \"*.corp.example\")")
+	flaggy.String(&listConfig, "", "config", "With -L: glob matched against the scan configuration name")
+	flaggy.String(&listInstance, "", "instance", "With -L: glob matched against the Burp instance address, \"unclaimed\" or \"all\"")
+	flaggy.String(&listSort, "", "sort", "With -L: sort by id, url, status, started, completed, instance, config, project or name (append :desc)")
+	flaggy.Int(&listLimit, "", "limit", "With -L: number of scans per page")
+	flaggy.Int(&listPage, "", "page", "With -L: page to show (default page size: 50)")
+	flaggy.String(&listOutput, "", "output", "With -L: output format table, wide, json or csv (default: table)")
+	flaggy.Int(&syncMisses, "", "sync-misses", "Consecutive unknown scan IDs that end discovery during sync (default: 10)")
+	flaggy.Int(&syncThreads, "", "sync-threads", "Concurrent Burp API requests during sync (default: 8)")
+	
+	// v1.3.0: Scan hook flags
+	flaggy.String(&preScanHook, "", "pre-scan-hook", "Command to run before each scan starts (receives scan details via BURP_CLI_* env vars and JSON stdin)")
+	flaggy.String(&postScanHook, "", "post-scan-hook", "Command to run after each scan completes (receives status, export paths and issue counts)")
+	flaggy.Int(&hookTimeout, "", "hook-timeout", "Timeout in seconds for scan hooks (default: 60)")
 	
 	// Hidden flag for adding test scans
 	flaggy.String(&addTestScan, "", "add-test-scan", "Add a test scan to history (format: scanID,url)")
@@ -245,7 +338,8 @@ func generateHTMLFilename(scanURL string) string {
 }
 
 // Helper function to generate HTML report from JSON export
-func generateHTMLReportFromJSON(jsonFilePath, scanURL, exportDir string) {
+// Returns the path of the generated report, or an empty string on failure
+func generateHTMLReportFromJSON(jsonFilePath, scanURL, exportDir string) string {
 	htmlFilename := generateHTMLFilename(scanURL)
 	htmlFilePath := exportDir + "/" + htmlFilename
 	
@@ -254,9 +348,10 @@ func generateHTMLReportFromJSON(jsonFilePath, scanURL, exportDir string) {
 	err := reporter.GenerateReport(jsonFilePath, htmlFilePath, "burp")
 	if err != nil {
 		fmt.Fprintf(color.Output, "%v Failed to generate HTML report: %v\n", red(" [-] ERROR:"), err)
-	} else {
-		fmt.Fprintf(color.Output, "%v HTML report generated: %v\n", green(" [+] SUCCESS:"), htmlFilePath)
+		return ""
 	}
+	fmt.Fprintf(color.Output, "%v HTML report generated: %v\n", green(" [+] SUCCESS:"), htmlFilePath)
+	return htmlFilePath
 }
 
 // Create export directory if it doesn't exist
@@ -275,51 +370,657 @@ func createExportDir() string {
 	return exportDir
 }
 
+// setupExportDir creates the export directory of auto-export; burp-export is
+// used when dir is empty. Returns an empty string if it cannot be created.
+func setupExportDir(dir string) string {
+	if dir == "" {
+		return createExportDir() // Use default burp-export
+	}
+	
+	// User specified directory, ensure it exists
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			fmt.Fprintf(color.Output, "%v Failed to create export directory %v: %v\n", red(" [-] ERROR:"), dir, err)
+			return ""
+		}
+		fmt.Fprintf(color.Output, "%v Using export directory: %v\n", green(" [+] SUCCESS:"), dir)
+	}
+	return dir
+}
+
+// scanRun holds the settings scans of one run are started and followed with.
+// The command line builds one from its flags and every scheduled execution its
+// own, so background monitors never see options of a later run.
+type scanRun struct {
+	target, port, apikey string
+	// options are the scan request options; URLs are set per scan
+	options configure.ScanOptions
+	// exportDir receives the exports of finished scans; empty disables auto-export
+	exportDir string
+	// Metadata stored with every scan of the run
+	tags           []string
+	project, notes string
+}
+
+// commandLineRun collects the scan options given on the command line
+func commandLineRun() *scanRun {
+	run := &scanRun{
+		target:  target,
+		port:    port,
+		apikey:  key,
+		tags:    scanner.SplitTags(scanTags),
+		project: strings.TrimSpace(scanProject),
+		notes:   strings.TrimSpace(scanNotes),
+		options: configure.ScanOptions{
+			Username:            username,
+			Password:            password,
+			ScanConfig:          scanConfig,
+			ScopeInclude:        scopeInclude,
+			ScopeExclude:        scopeExclude,
+			ScopeFile:           scopeFile,
+			ProtocolOption:      protocolOption,
+			CustomConfigFile:    customConfigFile,
+			BurpConfigName:      burpConfigName,
+			ConfigNumber:        configNumber,
+			ScanName:            scanName,
+			ResourcePool:        resourcePool,
+			CallbackURL:         callbackURL,
+			AdvancedScope:       advancedScope,
+			RecordedLoginScript: recordedLoginScript,
+		},
+	}
+	if autoExport {
+		run.exportDir = export
+	}
+	return run
+}
+
+// monitored reports whether the scans of the run are followed until they finish
+func (r *scanRun) monitored() bool {
+	return r.exportDir != "" || postScanHook != ""
+}
+
+// advanced reports whether any option requires ScanConfigAdvanced
+func (r *scanRun) advanced() bool {
+	o := r.options
+	return o.ConfigNumber > 0 || o.BurpConfigName != "" || o.CustomConfigFile != "" || o.ScanConfig != "" || o.ScopeInclude != "" || o.ScopeExclude != "" || o.ScopeFile != "" || o.ProtocolOption != "" || o.ScanName != "" || o.ResourcePool != "" || o.CallbackURL != "" || o.AdvancedScope || o.RecordedLoginScript != ""
+}
+
+// configName describes the scan configuration of the run
+func (r *scanRun) configName() string {
+	o := r.options
+	switch {
+	case o.ConfigNumber > 0:
+		if config, err := configure.FindConfigByNumber(o.ConfigNumber); err == nil {
+			return config.Name
+		}
+		return fmt.Sprintf("#%d", o.ConfigNumber)
+	case o.BurpConfigName != "":
+		return o.BurpConfigName
+	case o.CustomConfigFile != "":
+		return filepath.Base(o.CustomConfigFile)
+	}
+	return o.ScanConfig
+}
+
+// startScan runs the pre-scan hook and submits a new scan to Burp
+// Returns the scan ID, or an empty string if the scan was not started
+func startScan(run *scanRun, scanURL string) string {
+	return startSeededScan(run, scanURL, []string{scanURL})
+}
+
+// startSeededScan submits one scan seeded with several URLs
+// scanURL identifies the scan in hooks, tracking and export filenames
+func startSeededScan(run *scanRun, scanURL string, seeds []string) string {
+	// v1.3.0: A failing pre-scan hook skips the target
+	if preScanHook != "" {
+		payload := hooks.Payload{
+			Event:  hooks.EventPreScan,
+			URL:    scanURL,
+			Status: "pending",
+		}
+		if !hooks.RunLogged(preScanHook, payload, hookTimeoutDuration()) {
+			fmt.Fprintf(color.Output, "%v Skipping %v because the pre-scan hook failed\n", yellow(" [!] WARNING:"), scanURL)
+			return ""
+		}
+	}
+	
+	var Location string
+	var request map[string]interface{}
+	// v1.3.0: Seeds other than the scan URL itself, like a single HAR request,
+	// are only kept by the advanced request's urls list
+	seeded := len(seeds) > 1 || (len(seeds) == 1 && seeds[0] != scanURL)
+	
+	// v1.1.7: Check for any advanced options including OpenAPI compliance features
+	if run.advanced() || seeded {
+		options := run.options
+		options.URLs = seeds
+		Location, request = configure.SubmitScan(run.target, run.port, run.apikey, options)
+	} else {
+		Location = configure.ScanConfig(run.target, run.port, scanURL, run.options.Username, run.options.Password, run.apikey)
+		request = configure.SimpleScanRequest(scanURL, run.options.Username, run.options.Password)
+	}
+	
+	if Location == "" {
+		return ""
+	}
+	
+	scanID := extractScanID(Location)
+	fmt.Fprintf(color.Output, "%v Scanning %v with ID %v.\n", green(" [+] SUCCESS:"), scanURL, scanID)
+	trackScan(run, scanID, scanURL, request)
+	return scanID
+}
+
+// trackScan records a started scan with its metadata and masked request
+func trackScan(run *scanRun, scanID, scanURL string, request map[string]interface{}) {
+	tracker, err := scanner.NewScanTracker(scanner.NewInstance(run.target, run.port, run.apikey))
+	if err != nil {
+		fmt.Fprintf(color.Output, "%v Scan %v is not tracked: %v\n", yellow(" [!] WARNING:"), scanID, err)
+		return
+	}
+	
+	record := scanner.ScanRecord{
+		ScanID:     scanID,
+		URL:        scanURL,
+		ConfigName: run.configName(),
+		ScanName:   run.options.ScanName,
+		Tags:       run.tags,
+		Project:    run.project,
+		Notes:      run.notes,
+		Request:    configure.MaskScanRequest(request),
+	}
+	if err := tracker.AddScanRecord(record); err != nil {
+		fmt.Fprintf(color.Output, "%v Scan %v is not tracked: %v\n", yellow(" [!] WARNING:"), scanID, err)
+	}
+}
+
+// runSeededScan starts a scan seeded with several URLs and monitors it when needed
+func runSeededScan(run *scanRun, scanURL string, seeds []string) {
+	scanID := startSeededScan(run, scanURL, seeds)
+	if scanID == "" {
+		fmt.Fprintf(color.Output, "%v Can't start scan .\n", red(" [-] ERROR:"))
+		os.Exit(0)
+	}
+	
+	if run.monitored() {
+		monitorAndExport(run, scanID, scanURL)
+	}
+}
+
+// hookTimeoutDuration returns the configured hook timeout
+func hookTimeoutDuration() time.Duration {
+	if hookTimeout > 0 {
+		return time.Duration(hookTimeout) * time.Second
+	}
+	return hooks.DefaultTimeout
+}
+
+// runPostScanHook runs the post-scan hook for a finished scan
+func runPostScanHook(target, port, scanID, scanURL, status, jsonFilePath, htmlFilePath, apikey string) {
+	if postScanHook == "" {
+		return
+	}
+	
+	payload := hooks.Payload{
+		Event:      hooks.EventPostScan,
+		ScanID:     scanID,
+		URL:        scanURL,
+		Status:     status,
+		JSONExport: jsonFilePath,
+		HTMLExport: htmlFilePath,
+	}
+	if counts, err := commander.GetSeverityCounts(target, port, scanID, apikey); err == nil {
+		payload.SeverityCounts = counts
+	}
+	
+	hooks.RunLogged(postScanHook, payload, hookTimeoutDuration())
+}
+
+// recordScanResult stores the final snapshot of a completed scan
+func recordScanResult(tracker *scanner.ScanTracker, target, port, scanID, jsonFilePath, htmlFilePath, apikey string) {
+	snapshot, err := commander.GetScanSnapshot(target, port, scanID, apikey)
+	if err != nil {
+		fmt.Fprintf(color.Output, "%v Failed to record final metrics of scan %v: %v\n", yellow(" [!] WARNING:"), scanID, err)
+	}
+	
+	tracker.UpdateScan(scanID, func(record *scanner.ScanRecord) {
+		if record.CompletedAt.IsZero() {
+			record.CompletedAt = time.Now()
+		}
+		if snapshot != nil {
+			record.Metrics = snapshot.Metrics
+			record.SeverityCounts = snapshot.SeverityCounts
+		}
+		if jsonFilePath != "" {
+			record.JSONExport = absPath(jsonFilePath)
+		}
+		if htmlFilePath != "" {
+			record.HTMLExport = absPath(htmlFilePath)
+		}
+	})
+}
+
+// sampleRunningScans records a metric sample for every unfinished scan of an instance
+func sampleRunningScans(target, port, apikey string) (int, error) {
+	tracker, err := scanner.NewScanTracker(scanner.NewInstance(target, port, apikey))
+	if err != nil {
+		return 0, err
+	}
+	
+	return tracker.SampleRunningScans(func(scanID string) (string, map[string]interface{}, error) {
+		snapshot, err := commander.GetScanSnapshot(target, port, scanID, apikey)
+		if err != nil {
+			return "", nil, err
+		}
+		return snapshot.Status, snapshot.Metrics, nil
+	})
+}
+
+// absPath returns path as an absolute path when it can be resolved
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
 // Monitor scan and export when complete
-func monitorAndExport(target, port, scanID, scanURL, exportDir, apikey string) {
+func monitorAndExport(run *scanRun, scanID, scanURL string) {
+	monitorAndExportUntil(nil, run, scanID, scanURL)
+}
+
+// monitorAndExportUntil is monitorAndExport that stops following the scan
+// when stop is closed, after recording a last metric sample. The scan keeps
+// running in Burp. It returns the final status, or "" when it was stopped.
+func monitorAndExportUntil(stop <-chan struct{}, run *scanRun, scanID, scanURL string) string {
+	target, port, apikey, exportDir := run.target, run.port, run.apikey, run.exportDir
 	fmt.Fprintf(color.Output, "%v Monitoring scan %v...\n", cyan(" [i] INFO:"), scanID)
 	
-	// Track this scan
-	tracker, err := scanner.NewScanTracker()
-	if err == nil {
-		tracker.AddScan(scanID, scanURL, "", "")
-	}
+	// The scan was recorded when it was started
+	tracker, _ := scanner.NewScanTracker(scanner.NewInstance(target, port, apikey))
+	
+	// v1.3.0: Sample the scan metrics while polling for the metrics history
+	var lastSample time.Time
+	lastStatus := ""
+	
+	// v1.3.0: Follow the lifecycle to warn about paused or stuck scans
+	phaseStatus, phaseSince := "", time.Now()
+	warnedInitializing := false
 	
 	for {
-		status, err := configure.CheckScanStatus(target, port, scanID, apikey)
+		snapshot, err := commander.GetScanSnapshot(target, port, scanID, apikey)
 		if err != nil {
 			fmt.Fprintf(color.Output, "%v Error checking scan status: %v\n", red(" [-] ERROR:"), err)
 			if tracker != nil {
-				tracker.UpdateScanStatus(scanID, "failed")
+				tracker.UpdateScanStatus(scanID, scanner.StatusFailed)
 			}
-			return
+			runPostScanHook(target, port, scanID, scanURL, "failed", "", "", apikey)
+			return scanner.StatusFailed
 		}
 		
+		status := snapshot.Status
 		fmt.Fprintf(color.Output, "%v Scan status: %v\n", cyan(" [i] INFO:"), status)
 		
+		if status != phaseStatus {
+			phaseStatus, phaseSince = status, time.Now()
+			if status == scanner.StatusPaused {
+				fmt.Fprintf(color.Output, "%v Scan %v is paused; resume it in Burp to continue\n", yellow(" [!] WARNING:"), scanID)
+			}
+		}
+		if status == scanner.StatusInitializing && !warnedInitializing && time.Since(phaseSince) >= scanner.InitializingWarnAfter {
+			fmt.Fprintf(color.Output, "%v Scan %v has been initializing for %v; check the target and scan configuration\n", yellow(" [!] WARNING:"), scanID, time.Since(phaseSince).Round(time.Second))
+			warnedInitializing = true
+		}
+		
 		// Update scan status in tracker
 		if tracker != nil {
-			tracker.UpdateScanStatus(scanID, status)
+			transition, _ := tracker.TransitionScan(scanID, status)
+			if transition.Unknown {
+				fmt.Fprintf(color.Output, "%v Burp reported an unknown scan status: %q\n", yellow(" [!] WARNING:"), status)
+			} else if transition.Invalid {
+				fmt.Fprintf(color.Output, "%v Unexpected scan status change: %v -> %v\n", yellow(" [!] WARNING:"), transition.From, transition.To)
+			}
+			
+			if status != lastStatus || scanner.IsFinalStatus(status) || time.Since(lastSample) >= scanner.DefaultSampleInterval {
+				if err := tracker.RecordSample(scanID, scanner.NewMetricSample(status, snapshot.Metrics)); err != nil {
+					fmt.Fprintf(color.Output, "%v Failed to record metrics of scan %v: %v\n", yellow(" [!] WARNING:"), scanID, err)
+				}
+				lastSample = time.Now()
+				lastStatus = status
+			}
 		}
 		
-		if status == "succeeded" || status == "failed" {
+		// v1.3.0: Cancelled scans are final as well
+		if scanner.IsFinalStatus(status) {
 			fmt.Fprintf(color.Output, "%v Scan completed with status: %v\n", green(" [+] SUCCESS:"), status)
+			if tracker != nil {
+				printPhaseDurations(tracker.GetScanByID(scanID))
+			}
 			
-			if status == "succeeded" && exportDir != "" {
+			var jsonFilePath, htmlFilePath string
+			if status == scanner.StatusSucceeded && exportDir != "" {
 				filename := generateFilename(scanURL)
-				jsonFilePath := exportDir + "/" + filename
+				jsonFilePath = exportDir + "/" + filename
 				commander.GetScanWithFilename(target, port, scanID, exportDir, filename, apikey)
 				
 				// v1.2.0: Automatically generate HTML report from JSON export
-				generateHTMLReportFromJSON(jsonFilePath, scanURL, exportDir)
+				htmlFilePath = generateHTMLReportFromJSON(jsonFilePath, scanURL, exportDir)
 			}
-			break
+			
+			// v1.3.0: Keep the final metrics, issue counts and export paths in the history
+			if tracker != nil {
+				recordScanResult(tracker, target, port, scanID, jsonFilePath, htmlFilePath, apikey)
+			}
+			
+			// v1.3.0: Notify the post-scan hook with status, exports and issue counts
+			runPostScanHook(target, port, scanID, scanURL, status, jsonFilePath, htmlFilePath, apikey)
+			return status
 		}
 		
-		time.Sleep(10 * time.Second)
+		select {
+		case <-stop:
+			if tracker != nil {
+				if err := tracker.RecordSample(scanID, scanner.NewMetricSample(status, snapshot.Metrics)); err != nil {
+					fmt.Fprintf(color.Output, "%v Failed to record metrics of scan %v: %v\n", yellow(" [!] WARNING:"), scanID, err)
+				}
+			}
+			fmt.Fprintf(color.Output, "%v Stopped monitoring scan %v; it keeps running in Burp\n", cyan(" [i] INFO:"), scanID)
+			return ""
+		case <-time.After(10 * time.Second):
+		}
 	}
 }
 
+// printPhaseDurations prints how long a scan spent in each state
+func printPhaseDurations(record *scanner.ScanRecord) {
+	if record == nil {
+		return
+	}
+	var phases []string
+	for _, phase := range record.PhaseDurations(time.Now()) {
+		phases = append(phases, fmt.Sprintf("%s %v", phase.Status, phase.Duration.Round(time.Second)))
+	}
+	if len(phases) > 0 {
+		fmt.Fprintf(color.Output, "%v Phases: %v\n", cyan(" [i] INFO:"), strings.Join(phases, ", "))
+	}
+}
+
+// scheduleExecutor runs scheduled scans through the same launch, monitor and
+// export pipeline as the command line
+type scheduleExecutor struct {
+	target, port, apikey string
+	history              scheduler.HistoryStorage
+	
+	// stop ends the monitors started by the executor when the daemon exits
+	stop     chan struct{}
+	stopOnce sync.Once
+	monitors sync.WaitGroup
+}
+
+// newScheduleExecutor creates the executor of the scheduler daemon
+func newScheduleExecutor(target, port, apikey string, history scheduler.HistoryStorage) scheduler.Executor {
+	return &scheduleExecutor{
+		target:  target,
+		port:    port,
+		apikey:  apikey,
+		history: history,
+		stop:    make(chan struct{}),
+	}
+}
+
+// scheduledScan is a scan started by a schedule
+type scheduledScan struct {
+	id, url string
+}
+
+// ValidateSchedule checks the schedule, its input file and its parameters
+func (e *scheduleExecutor) ValidateSchedule(schedule *scheduler.Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	
+	config := schedule.ScanConfig
+	if config.ScanType == "url_list" || config.ScanType == "nmap" {
+		path, err := scheduler.ExpandPath(config.Target)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("cannot read %s: %v", config.Target, err)
+		}
+	}
+	
+	if value := config.Parameters["config_number"]; value != "" {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid config_number: %s", value)
+		}
+		if _, err := configure.FindConfigByNumber(number); err != nil {
+			return err
+		}
+	}
+	if value := config.Parameters["auto_export"]; value != "" {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid auto_export: %s", value)
+		}
+	}
+	return nil
+}
+
+// ExecuteSchedule starts the scans of a schedule and records the execution.
+// Exported scans are monitored in the background, and the record is completed
+// with their outcome once they finished.
+func (e *scheduleExecutor) ExecuteSchedule(schedule *scheduler.Schedule) error {
+	record := &scheduler.ExecutionRecord{
+		ScheduleID: schedule.ID,
+		ExecutedAt: time.Now(),
+	}
+	
+	run, scans, err := e.launch(schedule)
+	record.Duration = time.Since(record.ExecutedAt)
+	record.Success = err == nil
+	for _, scan := range scans {
+		record.ScanIDs = append(record.ScanIDs, scan.id)
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if run != nil && run.exportDir != "" && len(scans) > 0 {
+		record.ResultsPath = absPath(run.exportDir)
+	}
+	
+	if recordErr := e.history.AddRecord(record); recordErr != nil {
+		fmt.Fprintf(color.Output, "%v Failed to record execution: %v\n", yellow(" [!] WARNING:"), recordErr)
+	}
+	
+	// The daemon keeps running, so exports finish in the background
+	if len(scans) > 0 && run.monitored() {
+		e.monitor(record, run, scans)
+	}
+	return err
+}
+
+// GetExecutionHistory returns the recorded executions of a schedule
+func (e *scheduleExecutor) GetExecutionHistory(scheduleID string) ([]*scheduler.ExecutionRecord, error) {
+	return e.history.GetRecords(scheduleID)
+}
+
+// monitor follows the scans of an execution until they finished, then
+// updates its record with the total duration and the failed scans
+func (e *scheduleExecutor) monitor(record *scheduler.ExecutionRecord, run *scanRun, scans []scheduledScan) {
+	statuses := make([]string, len(scans))
+	
+	var execution sync.WaitGroup
+	for idx, scan := range scans {
+		e.monitors.Add(1)
+		execution.Add(1)
+		go func(idx int, scan scheduledScan) {
+			defer e.monitors.Done()
+			defer execution.Done()
+			statuses[idx] = monitorAndExportUntil(e.stop, run, scan.id, scan.url)
+		}(idx, scan)
+	}
+	
+	e.monitors.Add(1)
+	go func() {
+		defer e.monitors.Done()
+		execution.Wait()
+		
+		var failures []string
+		for idx, status := range statuses {
+			if status == "" {
+				// Stopped with the daemon; the outcome is unknown
+				return
+			}
+			if status != scanner.StatusSucceeded {
+				failures = append(failures, fmt.Sprintf("scan %s %s", scans[idx].id, status))
+			}
+		}
+		
+		record.Duration = time.Since(record.ExecutedAt)
+		if len(failures) > 0 {
+			record.Success = false
+			if record.Error != "" {
+				failures = append([]string{record.Error}, failures...)
+			}
+			record.Error = strings.Join(failures, "; ")
+		}
+		if err := e.history.UpdateRecord(record); err != nil {
+			fmt.Fprintf(color.Output, "%v Failed to record execution: %v\n", yellow(" [!] WARNING:"), err)
+		}
+	}()
+}
+
+// Checkpoint stops the monitors of running scans once they recorded a last
+// metric sample. Exports and post-scan hooks in progress finish first.
+func (e *scheduleExecutor) Checkpoint(timeout time.Duration) error {
+	e.stopOnce.Do(func() { close(e.stop) })
+	
+	done := make(chan struct{})
+	go func() {
+		e.monitors.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("scan monitors did not stop within %v", timeout)
+	}
+}
+
+// launch builds the options of a schedule's run and starts its scans
+func (e *scheduleExecutor) launch(schedule *scheduler.Schedule) (*scanRun, []scheduledScan, error) {
+	run, err := e.scheduleRun(schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	if !configure.CheckBurp(run.target, run.port, run.apikey) {
+		return nil, nil, fmt.Errorf("no Burp API endpoint found on %s", run.target+":"+run.port)
+	}
+	
+	urls, err := scheduleTargets(schedule.ScanConfig)
+	if err != nil {
+		return run, nil, err
+	}
+	
+	// Scans start while large target files are still being read
+	var scans []scheduledScan
+	total := 0
+	for scanURL, err := range urls {
+		if err != nil {
+			if len(scans) == 0 {
+				return run, nil, err
+			}
+			return run, scans, fmt.Errorf("%d scans started before target error: %v", len(scans), err)
+		}
+		total++
+		scanID := startScan(run, scanURL)
+		if scanID == "" {
+			fmt.Fprintf(color.Output, "%v Can't start scan over %s .\n", red(" [-] ERROR:"), scanURL)
+			continue
+		}
+		scans = append(scans, scheduledScan{id: scanID, url: scanURL})
+	}
+	
+	if total == 0 {
+		return run, nil, fmt.Errorf("no targets found in %s", schedule.ScanConfig.Target)
+	}
+	if len(scans) == 0 {
+		return run, nil, fmt.Errorf("no scan could be started")
+	}
+	if failed := total - len(scans); failed > 0 {
+		return run, scans, fmt.Errorf("%d of %d scans could not be started", failed, total)
+	}
+	return run, scans, nil
+}
+
+// scheduleRun applies the parameters of a schedule to the daemon's options.
+// Each execution gets its own run, so monitors of earlier executions keep
+// their instance and export settings.
+func (e *scheduleExecutor) scheduleRun(schedule *scheduler.Schedule) (*scanRun, error) {
+	params := schedule.ScanConfig.Parameters
+	
+	run := commandLineRun()
+	run.target, run.port, run.apikey = e.target, e.port, e.apikey
+	
+	run.options.ConfigNumber = 0
+	if value := params["config_number"]; value != "" {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config_number: %s", value)
+		}
+		run.options.ConfigNumber = number
+	}
+	run.options.BurpConfigName = params["burp_config"]
+	run.options.ScanName = params["scan_name"]
+	
+	run.exportDir = ""
+	if exportScans, _ := strconv.ParseBool(params["auto_export"]); exportScans {
+		dir, err := scheduler.ExpandPath(params["export_dir"])
+		if err != nil {
+			return nil, err
+		}
+		if run.exportDir = setupExportDir(dir); run.exportDir == "" {
+			fmt.Fprintf(color.Output, "%v Failed to setup export directory, disabling auto-export\n", red(" [-] ERROR:"))
+		}
+	}
+	
+	// Tracked scans point back to the schedule that started them, below any notes of their own
+	scheduleNote := fmt.Sprintf("Started by schedule %s (%s)", schedule.Name, schedule.ID)
+	if run.notes != "" {
+		run.notes += "\n" + scheduleNote
+	} else {
+		run.notes = scheduleNote
+	}
+	return run, nil
+}
+
+// scheduleTargets streams the URLs to scan for a schedule
+func scheduleTargets(config scheduler.ScanConfig) (iter.Seq2[string, error], error) {
+	if config.ScanType == "url" {
+		return func(yield func(string, error) bool) {
+			yield(config.Target, nil)
+		}, nil
+	}
+	
+	path, err := scheduler.ExpandPath(config.Target)
+	if err != nil {
+		return nil, err
+	}
+	
+	if config.ScanType == "nmap" {
+		return nmap.Targets(path), nil
+	}
+	
+	// url_list accepts the same plain lists and recon tool output as -sl
+	if _, err := importer.CheckCandidates(path, nil, importer.Options{}, importer.DefaultMaxCandidates); err != nil {
+		return nil, err
+	}
+	return importer.URLs(importer.Targets(path, importer.Options{}, nil)), nil
+}
+
 func main() {
 	// Handle version and scheduler commands before flaggy parsing
 	if len(os.Args) >= 2 {
@@ -336,6 +1037,15 @@ func main() {
 			os.Exit(0)
 		}
 		
+		// v1.3.0: Handle scan history commands
+		if arg == "scans" {
+			if err := scanner.NewScansCommand(target, port, key).HandleScansCommand(os.Args[2:]); err != nil {
+				fmt.Fprintf(color.Output, "%v %v\n", red(" [-] ERROR:"), err)
+				os.Exit(1)
+			}
+			return
+		}
+		
 		// Handle scheduler
 		if arg == "schedule" {
 			cli, err := scheduler.NewScheduleCLI()
@@ -343,6 +1053,11 @@ func main() {
 				fmt.Fprintf(color.Output, "%v Failed to initialize scheduler: %v\n", red(" [-] ERROR:"), err)
 				os.Exit(1)
 			}
+			// v1.3.0: Let the daemon sample the metrics of running scans
+			cli.SetBurpInstance(target, port, key)
+			cli.SetMetricsSampler(sampleRunningScans)
+			// v1.3.0: Due schedules run through the regular scan pipeline
+			cli.SetExecutorFactory(newScheduleExecutor)
 			if err := cli.HandleScheduleCommand(os.Args[2:]); err != nil {
 				fmt.Fprintf(color.Output, "%v Scheduler error: %v\n", red(" [-] ERROR:"), err)
 				os.Exit(1)
@@ -378,50 +1093,35 @@ func main() {
 	// v1.1.7: Smart export directory management
 	if autoExport {
 		// If user specified export directory, use it; otherwise use burp-export
-		if export == "" {
-			export = createExportDir() // Use default burp-export
-		} else {
-			// User specified directory, ensure it exists
-			if _, err := os.Stat(export); os.IsNotExist(err) {
-				err := os.MkdirAll(export, 0755)
-				if err != nil {
-					fmt.Fprintf(color.Output, "%v Failed to create export directory %v: %v\n", red(" [-] ERROR:"), export, err)
-					autoExport = false
-				} else {
-					fmt.Fprintf(color.Output, "%v Using export directory: %v\n", green(" [+] SUCCESS:"), export)
-				}
-			}
-		}
+		export = setupExportDir(export)
 		
 		if export == "" {
 			fmt.Fprintf(color.Output, "%v Failed to setup export directory, disabling auto-export\n", red(" [-] ERROR:"))
 			autoExport = false
 		}
 	}
+	// v1.3.0: Scans of this invocation share the options given on the command line
+	run := commandLineRun()
 
 	if nmapScan != "" {
-		scanList, err := nmap.ParseNmap(nmapScan)
-		if err != nil {
-			fmt.Fprintf(color.Output, "%v  %v.\n", red(" [-] ERROR:"), err)
-			os.Exit(0)
-		}
+		// v1.3.0: Targets are streamed so scans start while large files are parsed
+		started := 0
 		// v1.1.3+: Enhanced nmap scan processing with advanced configuration
-		for _, scan := range scanList {
-			var Location string
-			// v1.1.7: Support for all advanced options including OpenAPI features in nmap scans
-			if configNumber > 0 || burpConfigName != "" || customConfigFile != "" || scanConfig != "" || scopeInclude != "" || scopeExclude != "" || protocolOption != "" || scanName != "" || resourcePool != "" || callbackURL != "" || advancedScope || recordedLoginScript != "" {
-				Location = configure.ScanConfigAdvanced(target, port, scan, username, password, key, scanConfig, scopeInclude, scopeExclude, protocolOption, customConfigFile, burpConfigName, configNumber, scanName, resourcePool, callbackURL, advancedScope, recordedLoginScript)
-			} else {
-				Location = configure.ScanConfig(target, port, scan, username, password, key)
+		for scan, err := range nmap.Targets(nmapScan) {
+			if err != nil {
+				fmt.Fprintf(color.Output, "%v  %v.\n", red(" [-] ERROR:"), err)
+				if started == 0 {
+					os.Exit(0)
+				}
+				break
 			}
-			
-			if Location != "" {
-				scanID := extractScanID(Location)
-				fmt.Fprintf(color.Output, "%v Scanning %v with ID %v.\n", green(" [+] SUCCESS:"), scan, scanID)
-				
+			started++
+			// v1.3.0: Shared launch path runs the pre-scan hook before submitting
+			scanID := startScan(run, scan)
+			if scanID != "" {
 				// v1.1.1: Auto-export with goroutines for parallel processing
-				if autoExport {
-					go monitorAndExport(target, port, scanID, scan, export, key)
+				if run.monitored() {
+					go monitorAndExport(run, scanID, scan)
 				}
 			} else {
 				fmt.Fprintf(color.Output, "%v Can't start scan .\n", red(" [-] ERROR:"))
@@ -430,7 +1130,7 @@ func main() {
 		}
 		
 		// Wait for all scans to complete if auto-export is enabled
-		if autoExport {
+		if run.monitored() {
 			fmt.Fprintf(color.Output, "%v Waiting for all scans to complete...\n", cyan(" [i] INFO:"))
 			time.Sleep(5 * time.Second) // Give goroutines time to start
 			for {
@@ -439,33 +1139,116 @@ func main() {
 		}
 	}
 
-	if scanList != "" {
-		targets := nmap.ParseFile(scanList)
+	if scanList != "" || cidrTargets != "" {
+		// v1.3.0: Detect recon tool output (httpx, naabu, masscan) or fall back to a plain list
+		options := importer.Options{}
+		if matchStatus != "" {
+			codes, err := importer.ParseStatusCodes(matchStatus)
+			if err != nil {
+				fmt.Fprintf(color.Output, "%v %v.\n", red(" [-] ERROR:"), err)
+				os.Exit(0)
+			}
+			options.StatusCodes = codes
+		}
+		if matchTitle != "" {
+			titleRegex, err := regexp.Compile(matchTitle)
+			if err != nil {
+				fmt.Fprintf(color.Output, "%v Invalid title regex: %v.\n", red(" [-] ERROR:"), err)
+				os.Exit(0)
+			}
+			options.Title = titleRegex
+		}
+		if portList != "" {
+			ports, err := importer.ParsePorts(portList)
+			if err != nil {
+				fmt.Fprintf(color.Output, "%v %v.\n", red(" [-] ERROR:"), err)
+				os.Exit(0)
+			}
+			options.Ports = ports
+		}
+		
+		if probeTargets {
+			probeOptions := importer.ProbeOptions{Timeout: 1500 * time.Millisecond, Concurrency: 100}
+			if probeTimeout > 0 {
+				probeOptions.Timeout = time.Duration(probeTimeout) * time.Millisecond
+			}
+			if probeThreads > 0 {
+				probeOptions.Concurrency = probeThreads
+			}
+			options.Probe = &probeOptions
+		}
+		
+		ranges := strings.Fields(strings.Replace(cidrTargets, ";", " ", -1))
+		if probeTargets {
+			fmt.Fprintf(color.Output, "%v Probing expanded candidates for live web services...\n", cyan(" [i] INFO:"))
+		} else {
+			// Refuse before the first submission rather than flood Burp
+			limit := importer.DefaultMaxCandidates
+			if maxTargets > 0 {
+				limit = maxTargets
+			}
+			candidates, err := importer.CheckCandidates(scanList, ranges, options, limit)
+			if err != nil {
+				fmt.Fprintf(color.Output, "%v %v, use --probe to keep only live services or raise --max-targets.\n", red(" [-] ERROR:"), err)
+				os.Exit(0)
+			}
+			if candidates > 0 {
+				fmt.Fprintf(color.Output, "%v Ranges expanded into %v candidate URLs\n", cyan(" [i] INFO:"), candidates)
+			}
+		}
+		
+		// v1.3.0: Targets are streamed so scans start while large files are parsed
+		imported := &importer.Result{Format: importer.FormatList}
+		targets := func(yield func(importer.Target, error) bool) {
+			if scanList != "" {
+				for target, err := range importer.Targets(scanList, options, imported) {
+					if !yield(target, err) || err != nil {
+						return
+					}
+				}
+			}
+			for target, err := range importer.Ranges(ranges, options, imported) {
+				if !yield(target, err) || err != nil {
+					return
+				}
+			}
+		}
+		
+		started := 0
 		// v1.1.3+: Enhanced URL list processing with advanced configuration
-		for _, scan := range targets {
-			var Location string
-			// v1.1.7: Support for all advanced options including OpenAPI features in URL list scans
-			if configNumber > 0 || burpConfigName != "" || customConfigFile != "" || scanConfig != "" || scopeInclude != "" || scopeExclude != "" || protocolOption != "" || scanName != "" || resourcePool != "" || callbackURL != "" || advancedScope || recordedLoginScript != "" {
-				Location = configure.ScanConfigAdvanced(target, port, scan, username, password, key, scanConfig, scopeInclude, scopeExclude, protocolOption, customConfigFile, burpConfigName, configNumber, scanName, resourcePool, callbackURL, advancedScope, recordedLoginScript)
-			} else {
-				Location = configure.ScanConfig(target, port, scan, username, password, key)
+		for scan, err := range importer.URLs(targets) {
+			if err != nil {
+				fmt.Fprintf(color.Output, "%v %v.\n", red(" [-] ERROR:"), err)
+				if started == 0 {
+					os.Exit(0)
+				}
+				break
 			}
-			
-			if Location != "" {
-				scanID := extractScanID(Location)
-				fmt.Fprintf(color.Output, "%v Scanning %v with ID %v.\n", green(" [+] SUCCESS:"), scan, scanID)
-				
+			started++
+			// v1.3.0: Shared launch path runs the pre-scan hook before submitting
+			scanID := startScan(run, scan)
+			if scanID != "" {
 				// v1.1.1: Auto-export with goroutines for parallel processing
-				if autoExport {
-					go monitorAndExport(target, port, scanID, scan, export, key)
+				if run.monitored() {
+					go monitorAndExport(run, scanID, scan)
 				}
 			} else {
 				fmt.Fprintf(color.Output, "%v Can't start scan over %s .\n", red(" [-] ERROR:"), scan)
 			}
 		}
 		
+		if probeTargets && imported.Expanded > 0 {
+			fmt.Fprintf(color.Output, "%v %v live web services found in %v ranges\n", green(" [+] SUCCESS:"), imported.Live, imported.Expanded)
+		}
+		if imported.Format != importer.FormatList {
+			fmt.Fprintf(color.Output, "%v Imported %v targets from %v output (%v filtered, %v non-web ports skipped)\n", cyan(" [i] INFO:"), started, imported.Format, imported.Filtered, imported.Skipped)
+		}
+		if imported.Malformed > 0 {
+			fmt.Fprintf(color.Output, "%v Skipped %v lines of %v output that could not be parsed\n", yellow(" [!] WARNING:"), imported.Malformed, imported.Format)
+		}
+		
 		// Wait for all scans to complete if auto-export is enabled
-		if autoExport {
+		if run.monitored() {
 			fmt.Fprintf(color.Output, "%v Waiting for all scans to complete...\n", cyan(" [i] INFO:"))
 			time.Sleep(5 * time.Second)
 			for {
@@ -474,23 +1257,50 @@ func main() {
 		}
 	}
 
-	// v1.1.3+: Enhanced scan configuration with advanced options
-	if scan != "" {
-		var Location string
-		// v1.1.7: Check for any advanced options including OpenAPI compliance features
-		if configNumber > 0 || burpConfigName != "" || customConfigFile != "" || scanConfig != "" || scopeInclude != "" || scopeExclude != "" || protocolOption != "" || scanName != "" || resourcePool != "" || callbackURL != "" || advancedScope || recordedLoginScript != "" {
-			Location = configure.ScanConfigAdvanced(target, port, scan, username, password, key, scanConfig, scopeInclude, scopeExclude, protocolOption, customConfigFile, burpConfigName, configNumber, scanName, resourcePool, callbackURL, advancedScope, recordedLoginScript)
-		} else {
-			Location = configure.ScanConfig(target, port, scan, username, password, key)
+	// v1.3.0: Seed a single scan with every operation of an OpenAPI specification
+	if openapiSpec != "" {
+		seeds, err := openapi.ParseSpec(openapiSpec, openapiServer)
+		if err != nil {
+			fmt.Fprintf(color.Output, "%v %v.\n", red(" [-] ERROR:"), err)
+			os.Exit(0)
 		}
+		fmt.Fprintf(color.Output, "%v Loaded %v operations (%v unique URLs) from %v\n", cyan(" [i] INFO:"), len(seeds.Endpoints), len(seeds.URLs()), openapiSpec)
 		
-		if Location != "" {
-			scanID := extractScanID(Location)
-			fmt.Fprintf(color.Output, "%v Scanning %v with ID %v.\n", green(" [+] SUCCESS:"), scan, scanID)
-			
+		// Keep the crawler on the API unless the user provided a scope
+		if run.options.ScopeInclude == "" && run.options.ScopeFile == "" {
+			run.options.ScopeInclude = strings.Join(seeds.ScopeRules(), ",")
+		}
+		
+		runSeededScan(run, seeds.Servers[0], seeds.URLs())
+	}
+
+	// v1.3.0: Seed a single scan with the unique requests of a HAR capture
+	if harFile != "" {
+		var hosts []string
+		if harHosts != "" {
+			hosts = strings.Split(harHosts, ",")
+		}
+		seeds, err := har.ParseFile(harFile, har.Options{Hosts: hosts, KeepStatic: harKeepStatic})
+		if err != nil {
+			fmt.Fprintf(color.Output, "%v %v.\n", red(" [-] ERROR:"), err)
+			os.Exit(0)
+		}
+		fmt.Fprintf(color.Output, "%v Loaded %v unique URLs from %v (%v entries, %v static assets, %v out of scope)\n", cyan(" [i] INFO:"), len(seeds.URLs), harFile, seeds.Total, seeds.Static, seeds.Skipped)
+		
+		if harScope && run.options.ScopeInclude == "" && run.options.ScopeFile == "" {
+			run.options.ScopeInclude = strings.Join(seeds.ScopeRules(), ",")
+		}
+		
+		runSeededScan(run, seeds.Origins[0], seeds.URLs)
+	}
+
+	// v1.1.3+: Enhanced scan configuration with advanced options
+	if scan != "" {
+		scanID := startScan(run, scan)
+		if scanID != "" {
 			// v1.1.1: Auto-export functionality
-			if autoExport {
-				monitorAndExport(target, port, scanID, scan, export, key)
+			if run.monitored() {
+				monitorAndExport(run, scanID, scan)
 			}
 		} else {
 			fmt.Fprintf(color.Output, "%v Can't start scan .\n", red(" [-] ERROR:"))
@@ -561,7 +1371,8 @@ func main() {
 
 // handleScanManagement handles scan listing and bulk export operations
 func handleScanManagement() {
-	tracker, err := scanner.NewScanTracker()
+	// v1.3.0: History, sync and bulk export are scoped to the selected Burp instance
+	tracker, err := scanner.NewScanTracker(scanner.NewInstance(target, port, key))
 	if err != nil {
 		fmt.Fprintf(color.Output, "%v Failed to initialize scan tracker: %v\n", red(" [-] ERROR:"), err)
 		os.Exit(1)
@@ -573,7 +1384,7 @@ func handleScanManagement() {
 		// Try to sync with Burp API silently
 		if configure.CheckBurp(target, port, key) {
 			// Burp API is available, sync automatically
-			syncScansFromBurp(tracker, target, port, key, false) // false = silent mode
+			syncScansFromBurp(tracker, target, port, key, false, false) // false = silent mode, from the watermark
 		}
 		// If Burp API not available, just show cached history (offline mode)
 	}
@@ -589,7 +1400,7 @@ func handleScanManagement() {
 			os.Exit(1)
 		}
 		
-		imported := syncScansFromBurp(tracker, target, port, key, true) // true = verbose mode
+		imported := syncScansFromBurp(tracker, target, port, key, true, true) // true = verbose mode, full rescan
 		
 		if imported == 0 {
 			fmt.Fprintf(color.Output, "%v No scans found in Burp API\n", yellow(" [!] WARNING:"))
@@ -646,47 +1457,51 @@ func handleScanManagement() {
 		}
 	}
 	
-	// Get all scans
-	scans := tracker.GetAllScans()
-	
-	if len(scans) == 0 {
-		fmt.Fprintf(color.Output, "%v No scans found in history\n", cyan(" [i] INFO:"))
-		fmt.Fprintf(color.Output, "  Scans are automatically tracked when you start them with burp-cli\n")
-		return
+	// v1.3.0: Filter, sort and page the listing
+	query, err := scanQuery()
+	if err != nil {
+		fmt.Fprintf(color.Output, "%v %v\n", red(" [-] ERROR:"), err)
+		os.Exit(1)
+	}
+	format := strings.ToLower(listOutput)
+	if format == "" {
+		format = scanner.OutputTable
+	}
+	if !scanner.IsValidOutput(format) {
+		fmt.Fprintf(color.Output, "%v Invalid output format: %v (use table, wide, json or csv)\n", red(" [-] ERROR:"), listOutput)
+		os.Exit(1)
 	}
 	
-	// Display scan list
-	fmt.Fprintf(color.Output, "\n%v Tracked Scans (%d total):\n", cyan(" [i] INFO:"), len(scans))
-	fmt.Fprintf(color.Output, "═══════════════════════════════════════════════════════════════════════════════════\n")
-	fmt.Fprintf(color.Output, "%-6s %-40s %-12s %-20s\n", "Scan ID", "URL", "Status", "Start Time")
-	fmt.Fprintf(color.Output, "───────────────────────────────────────────────────────────────────────────────────\n")
+	scans, total, err := tracker.QueryScans(query)
+	if err != nil {
+		fmt.Fprintf(color.Output, "%v Failed to read scan history: %v\n", red(" [-] ERROR:"), err)
+		os.Exit(1)
+	}
 	
-	for _, scan := range scans {
-		// Truncate URL if too long
-		displayURL := scan.URL
-		if len(displayURL) > 38 {
-			displayURL = displayURL[:35] + "..."
-		}
-		
-		// Format time
-		timeStr := scan.StartTime.Format("2006-01-02 15:04")
-		
-		// Color code status
-		statusColor := cyan
-		switch scan.Status {
-		case "succeeded":
-			statusColor = green
-		case "failed":
-			statusColor = red
-		case "running":
-			statusColor = yellow
+	switch format {
+	case scanner.OutputJSON:
+		err = scanner.WriteScansJSON(os.Stdout, scans)
+	case scanner.OutputCSV:
+		err = scanner.WriteScansCSV(os.Stdout, scans)
+	default:
+		if total == 0 {
+			if query.Filtered() {
+				fmt.Fprintf(color.Output, "%v No scans match the given filters\n", cyan(" [i] INFO:"))
+			} else {
+				fmt.Fprintf(color.Output, "%v No scans found in history\n", cyan(" [i] INFO:"))
+				fmt.Fprintf(color.Output, "  Scans are automatically tracked when you start them with burp-cli\n")
+			}
+			return
 		}
-		
-		fmt.Fprintf(color.Output, "%-6s %-40s %v %-20s\n", 
-			scan.ScanID, displayURL, statusColor(scan.Status), timeStr)
+		printScanTable(scans, total, query, tracker.Instance(), format == scanner.OutputWide)
+	}
+	if err != nil {
+		fmt.Fprintf(color.Output, "%v Failed to write scan list: %v\n", red(" [-] ERROR:"), err)
+		os.Exit(1)
+	}
+	if len(scans) == 0 {
+		return
 	}
-	
-	fmt.Fprintf(color.Output, "═══════════════════════════════════════════════════════════════════════════════════\n\n")
 	
 	// Handle list and export all
 	if listAndExportAll {
@@ -708,11 +1523,23 @@ func handleScanManagement() {
 		
 		successCount := 0
 		failCount := 0
+		session, err := tracker.Session()
+		if err != nil {
+			fmt.Fprintf(color.Output, "%v %v\n", red(" [-] ERROR:"), err)
+			os.Exit(1)
+		}
 		
 		for i, scan := range scans {
 			fmt.Fprintf(color.Output, "[%d/%d] Processing Scan ID %s (%s)...\n", 
 				i+1, len(scans), scan.ScanID, scan.URL)
 			
+			// v1.3.0: Burp reused the IDs of scans from before its last reset
+			if !tracker.Current(scan, session) {
+				fmt.Fprintf(color.Output, "  %v Skipped: the scan is from before Burp started its scan IDs over\n\n", yellow("⚠"))
+				failCount++
+				continue
+			}
+			
 			// Export JSON from Burp API
 			jsonFile := filepath.Join(exportDir, fmt.Sprintf("scan_%s.json", scan.ScanID))
 			
@@ -723,6 +1550,11 @@ func handleScanManagement() {
 				continue
 			}
 			
+			// v1.3.0: Record the export so retention policies clean it up
+			tracker.UpdateScan(scan.ScanID, func(record *scanner.ScanRecord) {
+				record.AddExports(absPath(jsonFile))
+			})
+			
 			// Generate HTML report
 			htmlFile := filepath.Join(exportDir, fmt.Sprintf("scan_%s_report.html", scan.ScanID))
 			
@@ -732,6 +1564,10 @@ func handleScanManagement() {
 				continue
 			}
 			
+			tracker.UpdateScan(scan.ScanID, func(record *scanner.ScanRecord) {
+				record.AddExports(absPath(htmlFile))
+			})
+			
 			fmt.Fprintf(color.Output, "  %v JSON: %s\n", green("✓"), jsonFile)
 			fmt.Fprintf(color.Output, "  %v HTML: %s\n\n", green("✓"), htmlFile)
 			successCount++
@@ -744,6 +1580,157 @@ func handleScanManagement() {
 	}
 }
 
+// printScanTable prints the scan listing as a table. The wide format shows
+// full URLs together with completion time and configuration.
+func printScanTable(scans []scanner.ScanRecord, total int, query scanner.ScanQuery, instance scanner.Instance, wide bool) {
+	scope := fmt.Sprintf("on Burp instance %v", instance)
+	if query.Instance != "" {
+		scope = fmt.Sprintf("on Burp instances matching %q", query.Instance)
+	}
+	shown := fmt.Sprintf("%d total", total)
+	if len(scans) < total {
+		first := (query.Page - 1) * query.Limit
+		if first < 0 {
+			first = 0
+		}
+		shown = fmt.Sprintf("%d-%d of %d, page %d", first+1, first+len(scans), total, (first/query.Limit)+1)
+	}
+	
+	urlWidth := 40
+	if wide {
+		for _, scan := range scans {
+			if len(scan.URL)+2 > urlWidth {
+				urlWidth = len(scan.URL) + 2
+			}
+		}
+	}
+	
+	fmt.Fprintf(color.Output, "\n%v Tracked Scans (%v) %v:\n", cyan(" [i] INFO:"), shown, scope)
+	fmt.Fprintf(color.Output, "═══════════════════════════════════════════════════════════════════════════════════\n")
+	if wide {
+		fmt.Fprintf(color.Output, "%-6s %-*s %-12s %-20s %-20s %-24s %-s\n", "Scan ID", urlWidth, "URL", "Status", "Start Time", "Completed", "Configuration", "Instance")
+	} else {
+		fmt.Fprintf(color.Output, "%-6s %-40s %-12s %-20s %-s\n", "Scan ID", "URL", "Status", "Start Time", "Instance")
+	}
+	fmt.Fprintf(color.Output, "───────────────────────────────────────────────────────────────────────────────────\n")
+	
+	for _, scan := range scans {
+		// Truncate URL if too long
+		displayURL := scan.URL
+		if !wide && len(displayURL) > 38 {
+			displayURL = displayURL[:35] + "..."
+		}
+		
+		// Format time
+		timeStr := scan.StartTime.Format("2006-01-02 15:04")
+		
+		// Color code status
+		statusColor := scanStatusColor(scan.Status)
+		
+		// Records from before instance tracking are claimed on their next update
+		owner := scan.InstanceID()
+		if owner == "" {
+			owner = "unclaimed"
+		}
+		
+		if wide {
+			completed := "-"
+			if !scan.CompletedAt.IsZero() {
+				completed = scan.CompletedAt.Format("2006-01-02 15:04")
+			}
+			config := scan.ConfigName
+			if config == "" {
+				config = "-"
+			}
+			fmt.Fprintf(color.Output, "%-6s %-*s %v %-20s %-20s %-24s %v\n", 
+				scan.ScanID, urlWidth, displayURL, statusColor(fmt.Sprintf("%-12s", scan.Status)), timeStr, completed, config, owner)
+		} else {
+			fmt.Fprintf(color.Output, "%-6s %-40s %v %-20s %v\n", 
+				scan.ScanID, displayURL, statusColor(fmt.Sprintf("%-12s", scan.Status)), timeStr, owner)
+		}
+		
+		// v1.3.0: Metadata and final issue counts below the scan line
+		var details []string
+		if scan.Project != "" {
+			details = append(details, "project: "+scan.Project)
+		}
+		if len(scan.Tags) > 0 {
+			details = append(details, "tags: "+strings.Join(scan.Tags, ", "))
+		}
+		if summary := scan.SeveritySummary(); summary != "" {
+			details = append(details, "issues: "+summary)
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(color.Output, "       %v\n", strings.Join(details, " | "))
+		}
+		// v1.3.0: Paused, stuck and unknown states
+		for _, warning := range scan.LifecycleWarnings(time.Now()) {
+			fmt.Fprintf(color.Output, "       %v %v\n", yellow("⚠"), warning)
+		}
+		if scan.Notes != "" {
+			notes := strings.ReplaceAll(scan.Notes, "\n", " / ")
+			if !wide && len(notes) > 74 {
+				notes = notes[:71] + "..."
+			}
+			fmt.Fprintf(color.Output, "       notes: %v\n", notes)
+		}
+	}
+	
+	fmt.Fprintf(color.Output, "═══════════════════════════════════════════════════════════════════════════════════\n\n")
+}
+
+// scanStatusColor colors finished, failed, paused, in-progress and unknown states apart
+func scanStatusColor(status string) func(format string, a ...interface{}) string {
+	switch status {
+	case scanner.StatusSucceeded:
+		return green
+	case scanner.StatusFailed, scanner.StatusCancelled:
+		return red
+	case scanner.StatusPaused:
+		return magenta
+	case scanner.StatusRunning, scanner.StatusInitializing, scanner.StatusCrawling, scanner.StatusAuditing:
+		return yellow
+	}
+	return cyan
+}
+
+// scanQuery builds the scan listing query from the command line filters
+func scanQuery() (scanner.ScanQuery, error) {
+	query := scanner.ScanQuery{
+		URL:      listURL,
+		Tags:     scanner.SplitTags(scanTags),
+		Project:  scanProject,
+		Config:   listConfig,
+		Instance: listInstance,
+		Limit:    listLimit,
+		Page:     listPage,
+	}
+	for _, status := range strings.Split(listStatus, ",") {
+		if status = strings.ToLower(strings.TrimSpace(status)); status != "" {
+			query.Statuses = append(query.Statuses, status)
+		}
+	}
+	
+	var err error
+	now := time.Now()
+	if query.Since, err = scanner.ParseTimeBound(listSince, now); err != nil {
+		return query, err
+	}
+	if query.Until, err = scanner.ParseTimeBound(listUntil, now); err != nil {
+		return query, err
+	}
+	if query.SortBy, query.Descending, err = scanner.ParseSort(listSort); err != nil {
+		return query, err
+	}
+	if listLimit < 0 || listPage < 0 {
+		return query, fmt.Errorf("--limit and --page must not be negative")
+	}
+	if listPage > 0 && listLimit == 0 {
+		query.Limit = defaultPageSize
+	}
+	return query, nil
+}
+
 // exportScanToJSON exports a scan's results to JSON file
 func exportScanToJSON(scanID, outputFile string) error {
 	// Create temp directory for export
@@ -763,68 +1750,129 @@ func exportScanToJSON(scanID, outputFile string) error {
 }
 
 // syncScansFromBurp syncs scan history with Burp API
+// New scan IDs are discovered upwards from the instance's watermark until
+// --sync-misses consecutive IDs are unknown
 // verbose: if true, prints detailed import messages; if false, syncs silently
-func syncScansFromBurp(tracker *scanner.ScanTracker, target, port, apikey string, verbose bool) int {
+// full: if true, discovery starts at scan ID 1 instead of the watermark
+func syncScansFromBurp(tracker *scanner.ScanTracker, target, port, apikey string, verbose, full bool) int {
 	imported := 0
 	updated := 0
 	
-	// Try scan IDs from 1 to 50
-	for i := 1; i <= 50; i++ {
-		scanID := fmt.Sprintf("%d", i)
-		
-		// Try to get scan status
-		status, err := configure.CheckScanStatus(target, port, scanID, apikey)
+	probe := func(id int) (*scanner.RemoteScan, error) {
+		return probeBurpScan(target, port, strconv.Itoa(id), apikey)
+	}
+	
+	// v1.3.0: Burp starts its scan IDs over after a restart or a project
+	// change. If the newest known scan is gone, a new session begins before
+	// anything is updated, so reused IDs never touch the older records.
+	// Histories from before watermarks start at their highest tracked ID.
+	tracked := tracker.GetAllScans()
+	watermark, stored := tracker.Watermark()
+	if !stored {
+		watermark = scanner.HighestScanID(tracked)
+	}
+	start := 1
+	if watermark > 0 {
+		remote, err := probe(watermark)
 		if err != nil {
-			// Scan doesn't exist, skip
-			continue
+			if verbose {
+				fmt.Fprintf(color.Output, "%v Sync aborted: %v\n", red(" [-] ERROR:"), err)
+			}
+			return 0
+		}
+		if remote == nil {
+			if _, err := tracker.BeginSession(time.Now()); err != nil {
+				if verbose {
+					fmt.Fprintf(color.Output, "%v Sync aborted: %v\n", red(" [-] ERROR:"), err)
+				}
+				return 0
+			}
+			if verbose {
+				fmt.Fprintf(color.Output, "%v Scan %d is gone, Burp started its scan IDs over. Earlier scans are kept as they are.\n", yellow(" [!] WARNING:"), watermark)
+			}
+			watermark, stored = 0, false
+		} else if !full {
+			start = watermark + 1
+		}
+	}
+	
+	// v1.3.0: Only re-check tracked scans of the current session whose
+	// status or URL can still change
+	session, err := tracker.Session()
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(color.Output, "%v Sync aborted: %v\n", red(" [-] ERROR:"), err)
+		}
+		return 0
+	}
+	var refresh []int
+	for _, record := range tracked {
+		id, err := strconv.Atoi(record.ScanID)
+		if err == nil && tracker.Current(record, session) && (!scanner.IsFinalStatus(record.Status) || strings.HasPrefix(record.URL, "scan_")) {
+			refresh = append(refresh, id)
+		}
+	}
+	current, err := scanner.ProbeAll(probe, refresh, syncThreads)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(color.Output, "%v Sync aborted: %v\n", red(" [-] ERROR:"), err)
+		}
+		return 0
+	}
+	for _, id := range scanner.SortedIDs(current) {
+		if syncTrackedScan(tracker, current[id]) {
+			updated++
+		}
+	}
+	
+	found, err := scanner.Discover(probe, scanner.DiscoveryOptions{
+		Start:       start,
+		Misses:      syncMisses,
+		Concurrency: syncThreads,
+	})
+	if err != nil && verbose {
+		fmt.Fprintf(color.Output, "%v Discovery stopped early: %v\n", yellow(" [!] WARNING:"), err)
+	}
+	
+	highest := start - 1
+	for _, remote := range found {
+		if remote.ID > highest {
+			highest = remote.ID
 		}
+		scanID := strconv.Itoa(remote.ID)
 		
 		// Check if already tracked
-		existing := tracker.GetScanByID(scanID)
-		
-		if existing != nil {
-			// Update status if changed
-			if existing.Status != status {
-				tracker.UpdateScanStatus(scanID, status)
+		if tracker.GetScanByID(scanID) != nil {
+			if syncTrackedScan(tracker, remote) {
 				updated++
 			}
-			
-			// Update URL if it was generic (scan_X) and we can get a real URL now
-			if strings.HasPrefix(existing.URL, "scan_") {
-				scanURL := getScanURL(target, port, scanID, apikey)
-				if scanURL == "" {
-					scanURL = getScanURLFromIssues(target, port, scanID, apikey)
-				}
-				if scanURL != "" && !strings.HasPrefix(scanURL, "scan_") {
-					// Update with real URL
-					existing.URL = scanURL
-					tracker.UpdateScanStatus(scanID, status) // This saves the tracker
-				}
-			}
 			continue
 		}
 		
-		// New scan - get URL and add to tracker
-		scanURL := getScanURL(target, port, scanID, apikey)
+		// New scan - fall back to the issues for its URL
+		scanURL := remote.URL
 		if scanURL == "" {
-			// If we can't get the URL, try to get it from issues
 			scanURL = getScanURLFromIssues(target, port, scanID, apikey)
 		}
 		if scanURL == "" {
 			scanURL = fmt.Sprintf("scan_%s", scanID)
 		}
 		
-		// Add to tracker
-		if err := tracker.AddScan(scanID, scanURL, "", ""); err == nil {
-			// Update with correct status
-			tracker.UpdateScanStatus(scanID, status)
+		// Add to tracker with its current status; the completion time of
+		// scans that finished before the import is unknown
+		if err := tracker.AddScanRecord(scanner.ScanRecord{ScanID: scanID, URL: scanURL, Status: remote.Status}); err == nil {
 			imported++
 			if verbose {
-				fmt.Fprintf(color.Output, "  %v Imported scan %s: %s (%s)\n", green("✓"), scanID, scanURL, status)
+				fmt.Fprintf(color.Output, "  %v Imported scan %s: %s (%s)\n", green("✓"), scanID, scanURL, remote.Status)
 			}
 		}
 	}
 	
+	// Persist the watermark unless discovery was cut short before reaching it
+	if err == nil && (highest != watermark || !stored) {
+		tracker.SetWatermark(highest)
+	}
+	
 	// Show sync summary only in verbose mode
 	if verbose && (imported > 0 || updated > 0) {
 		if imported > 0 && updated > 0 {
@@ -839,9 +1887,31 @@ func syncScansFromBurp(tracker *scanner.ScanTracker, target, port, apikey string
 	return imported
 }
 
-// getScanURL retrieves the URL from a scan
-func getScanURL(target, port, scanID, apikey string) string {
-	// Build endpoint URL
+// syncTrackedScan applies the current status and URL of a tracked scan.
+// Returns true if the status changed.
+func syncTrackedScan(tracker *scanner.ScanTracker, remote scanner.RemoteScan) bool {
+	scanID := strconv.Itoa(remote.ID)
+	existing := tracker.GetScanByID(scanID)
+	if existing == nil {
+		return false
+	}
+	
+	// Update URL if it was generic (scan_X) and we can get a real URL now
+	if strings.HasPrefix(existing.URL, "scan_") && remote.URL != "" {
+		tracker.UpdateScanURL(scanID, remote.URL)
+	}
+	
+	// Update status if changed
+	if existing.Status != remote.Status && remote.Status != "" {
+		tracker.UpdateScanStatus(scanID, remote.Status)
+		return true
+	}
+	return false
+}
+
+// probeBurpScan fetches the status and URL of a scan in one request.
+// Returns nil if Burp does not know the scan ID.
+func probeBurpScan(target, port, scanID, apikey string) (*scanner.RemoteScan, error) {
 	var endpoint string
 	if apikey != "" {
 		endpoint = fmt.Sprintf("http://%s:%s/%s/v0.1/scan/%s", target, port, apikey, scanID)
@@ -849,26 +1919,31 @@ func getScanURL(target, port, scanID, apikey string) string {
 		endpoint = fmt.Sprintf("http://%s:%s/v0.1/scan/%s", target, port, scanID)
 	}
 	
-	// Try to get scan data
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(endpoint)
+	resp, err := syncClient.Get(endpoint)
 	if err != nil {
-		return ""
+		return nil, err
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode != 200 {
-		return ""
+		return nil, nil
 	}
 	
-	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return ""
+		return nil, err
 	}
 	
-	bodyStr := string(body)
-	
+	id, _ := strconv.Atoi(scanID)
+	return &scanner.RemoteScan{
+		ID:     id,
+		Status: gjson.GetBytes(body, "scan_status").String(),
+		URL:    scanURLFromBody(string(body)),
+	}, nil
+}
+
+// scanURLFromBody extracts the scanned URL from a scan response
+func scanURLFromBody(bodyStr string) string {
 	// Use gjson to extract URL from scan_metrics.current_url
 	currentURL := gjson.Get(bodyStr, "scan_metrics.current_url")
 	if currentURL.Exists() && currentURL.String() != "" {
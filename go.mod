@@ -8,6 +8,9 @@ require (
 	github.com/joanbono/color v1.7.0
 	github.com/tidwall/gjson v1.18.0
 	github.com/tomsteele/go-nmap v0.0.0-20191202052157-3507e0b03523
+	go.etcd.io/bbolt v1.5.0
+	golang.org/x/sys v0.45.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -15,5 +18,4 @@ require (
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.0 // indirect
-	golang.org/x/sys v0.29.0 // indirect
 )
@@ -162,3 +162,54 @@ func GetScanWithFilename(target, port, Location, exportFolder, exportFilename, a
 		}
 	}
 }
+
+// ScanSnapshot is the state of a scan at one point in time
+type ScanSnapshot struct {
+	Status         string
+	Metrics        map[string]interface{}
+	SeverityCounts map[string]int
+}
+
+// GetSeverityCounts returns the number of issues per severity for a given scan
+func GetSeverityCounts(target, port, Location, apikey string) (map[string]int, error) {
+	snapshot, err := GetScanSnapshot(target, port, Location, apikey)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.SeverityCounts, nil
+}
+
+// GetScanSnapshot returns the status, scan_metrics and severity counts of a given scan
+func GetScanSnapshot(target, port, Location, apikey string) (*ScanSnapshot, error) {
+	var endpoint string
+	if apikey != "" {
+		endpoint = "http://" + target + ":" + port + "/" + apikey + "/v0.1/scan/" + Location
+	} else {
+		endpoint = "http://" + target + ":" + port + "/v0.1/scan/" + Location
+	}
+
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("scan ID %v not found", Location)
+	}
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	snapshot := &ScanSnapshot{
+		Status:         gjson.GetBytes(body, "scan_status").String(),
+		Metrics:        map[string]interface{}{},
+		SeverityCounts: map[string]int{"high": 0, "medium": 0, "low": 0, "info": 0},
+	}
+	if metrics, ok := gjson.GetBytes(body, "scan_metrics").Value().(map[string]interface{}); ok {
+		snapshot.Metrics = metrics
+	}
+	for _, severity := range gjson.GetBytes(body, "issue_events.#.issue.severity").Array() {
+		snapshot.SeverityCounts[severity.String()]++
+	}
+
+	return snapshot, nil
+}
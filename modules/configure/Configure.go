@@ -71,16 +71,13 @@ func ScanConfig(target, port, urls, username, password, apikey string) (ScanLoca
 	} else {
 		endpoint = "http://" + target + ":" + port + "/v0.1/scan"
 	}
-	var url_string string
 	// At the moment, this only allows 1 url to be scanned
 	if username == "" && password == "" {
 		fmt.Fprintf(color.Output, " %v Setting up scanner...\n", cyan("[i] INFO"))
-		url_string = `{"urls":["` + urls + `"]}`
 	} else {
 		fmt.Fprintf(color.Output, " %v Setting up scanner using credentials %v:%v\n", cyan("[i] INFO"), username, password)
-		url_string = `{"application_logins":[{"password":"` + password + `","username":"` + username + `"}],"urls":["` + urls + `"]}`
 	}
-	var body = []byte(url_string)
+	body, _ := json.Marshal(SimpleScanRequest(urls, username, password))
 
 	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -103,6 +100,19 @@ func ScanConfig(target, port, urls, username, password, apikey string) (ScanLoca
 	return Location
 }
 
+// SimpleScanRequest returns the request ScanConfig sends for a single URL
+func SimpleScanRequest(urls, username, password string) map[string]interface{} {
+	scanRequest := map[string]interface{}{
+		"urls": []string{urls},
+	}
+	if username != "" || password != "" {
+		scanRequest["application_logins"] = []map[string]interface{}{
+			{"username": username, "password": password},
+		}
+	}
+	return scanRequest
+}
+
 // Get issue description from Burp's database
 func GetDescription(target, port, issueName, apikey string) {
 	var endpoint string
@@ -164,8 +174,57 @@ func CheckScanStatus(target, port, scanID, apikey string) (status string, err er
 	return status, nil
 }
 
+// ScanOptions holds every option that can be sent with a new scan request
+type ScanOptions struct {
+	URLs                []string
+	Username            string
+	Password            string
+	ScanConfig          string
+	ScopeInclude        string
+	ScopeExclude        string
+	ScopeFile           string
+	ProtocolOption      string
+	CustomConfigFile    string
+	BurpConfigName      string
+	ConfigNumber        int
+	ScanName            string
+	ResourcePool        string
+	CallbackURL         string
+	AdvancedScope       bool
+	RecordedLoginScript string
+}
+
 // ScanConfigAdvanced configures a new scan with advanced options including OpenAPI compliance features
 func ScanConfigAdvanced(target, port, urls, username, password, apikey, scanConfig, scopeInclude, scopeExclude, protocolOption, customConfigFile, burpConfigName string, configNumber int, scanName, resourcePool, callbackURL string, advancedScope bool, recordedLoginScript string) (ScanLocation string) {
+	return ScanConfigWithOptions(target, port, apikey, ScanOptions{
+		URLs:                []string{urls},
+		Username:            username,
+		Password:            password,
+		ScanConfig:          scanConfig,
+		ScopeInclude:        scopeInclude,
+		ScopeExclude:        scopeExclude,
+		ProtocolOption:      protocolOption,
+		CustomConfigFile:    customConfigFile,
+		BurpConfigName:      burpConfigName,
+		ConfigNumber:        configNumber,
+		ScanName:            scanName,
+		ResourcePool:        resourcePool,
+		CallbackURL:         callbackURL,
+		AdvancedScope:       advancedScope,
+		RecordedLoginScript: recordedLoginScript,
+	})
+}
+
+// ScanConfigWithOptions configures a new scan from a ScanOptions value and returns the location
+func ScanConfigWithOptions(target, port, apikey string, opts ScanOptions) (ScanLocation string) {
+	ScanLocation, _ = SubmitScan(target, port, apikey, opts)
+	return ScanLocation
+}
+
+// SubmitScan configures a new scan from a ScanOptions value and returns the
+// location together with the scan request that was sent. The request still
+// contains credentials; use MaskScanRequest before storing it.
+func SubmitScan(target, port, apikey string, opts ScanOptions) (string, map[string]interface{}) {
 	var endpoint string
 	if apikey != "" {
 		endpoint = "http://" + target + ":" + port + "/" + apikey + "/v0.1/scan"
@@ -177,23 +236,23 @@ func ScanConfigAdvanced(target, port, urls, username, password, apikey, scanConf
 	scanRequest := make(map[string]interface{})
 	
 	// URLs array
-	scanRequest["urls"] = []string{urls}
+	scanRequest["urls"] = opts.URLs
 	
 	// v1.1.7: Scan name support
-	if scanName != "" {
-		scanRequest["name"] = scanName
-		fmt.Fprintf(color.Output, " %v Scan name: %v\n", cyan("[i] INFO"), scanName)
+	if opts.ScanName != "" {
+		scanRequest["name"] = opts.ScanName
+		fmt.Fprintf(color.Output, " %v Scan name: %v\n", cyan("[i] INFO"), opts.ScanName)
 	}
 	
 	// Application logins - support both username/password and recorded login scripts
 	var logins []map[string]interface{}
 	
 	// v1.1.7: Recorded login script support
-	if recordedLoginScript != "" {
-		scriptData, err := ioutil.ReadFile(recordedLoginScript)
+	if opts.RecordedLoginScript != "" {
+		scriptData, err := ioutil.ReadFile(opts.RecordedLoginScript)
 		if err != nil {
-			fmt.Fprintf(color.Output, "%v Error reading recorded login script %v: %v\n", red(" [-] ERROR:"), recordedLoginScript, err)
-			return ""
+			fmt.Fprintf(color.Output, "%v Error reading recorded login script %v: %v\n", red(" [-] ERROR:"), opts.RecordedLoginScript, err)
+			return "", nil
 		}
 		
 		logins = append(logins, map[string]interface{}{
@@ -201,17 +260,17 @@ func ScanConfigAdvanced(target, port, urls, username, password, apikey, scanConf
 			"label":  "Recorded Login",
 			"script": string(scriptData),
 		})
-		fmt.Fprintf(color.Output, " %v Using recorded login script: %v\n", cyan("[i] INFO"), recordedLoginScript)
+		fmt.Fprintf(color.Output, " %v Using recorded login script: %v\n", cyan("[i] INFO"), opts.RecordedLoginScript)
 	}
 	
 	// Username/password login
-	if username != "" && password != "" {
+	if opts.Username != "" && opts.Password != "" {
 		logins = append(logins, map[string]interface{}{
 			"type":     "UsernameAndPasswordLogin",
-			"username": username,
-			"password": password,
+			"username": opts.Username,
+			"password": opts.Password,
 		})
-		fmt.Fprintf(color.Output, " %v Using credentials: %v:%v\n", cyan("[i] INFO"), username, password)
+		fmt.Fprintf(color.Output, " %v Using credentials: %v:%v\n", cyan("[i] INFO"), opts.Username, opts.Password)
 	}
 	
 	if len(logins) > 0 {
@@ -224,11 +283,11 @@ func ScanConfigAdvanced(target, port, urls, username, password, apikey, scanConf
 	var configs []map[string]interface{}
 	
 	// Configuration number shortcut (v1.1.6) - highest priority
-	if configNumber > 0 {
-		configItem, err := FindConfigByNumber(configNumber)
+	if opts.ConfigNumber > 0 {
+		configItem, err := FindConfigByNumber(opts.ConfigNumber)
 		if err != nil {
 			fmt.Fprintf(color.Output, "%v %v\n", red(" [-] ERROR:"), err)
-			return ""
+			return "", nil
 		}
 		
 		switch configItem.Type {
@@ -237,63 +296,63 @@ func ScanConfigAdvanced(target, port, urls, username, password, apikey, scanConf
 				"type": "NamedConfiguration",
 				"name": configItem.Name,
 			})
-			fmt.Fprintf(color.Output, " %v Using built-in configuration #%v: %v\n", cyan("[i] INFO"), configNumber, configItem.Name)
+			fmt.Fprintf(color.Output, " %v Using built-in configuration #%v: %v\n", cyan("[i] INFO"), opts.ConfigNumber, configItem.Name)
 		case "burp", "custom":
 			configData, err := ioutil.ReadFile(configItem.Path)
 			if err != nil {
 				fmt.Fprintf(color.Output, "%v Error reading config file %v: %v\n", red(" [-] ERROR:"), configItem.Path, err)
-				return ""
+				return "", nil
 			}
 			configs = append(configs, map[string]interface{}{
 				"type":   "CustomConfiguration",
 				"config": string(configData),
 			})
-			fmt.Fprintf(color.Output, " %v Using %v configuration #%v: %v (%v)\n", cyan("[i] INFO"), configItem.Type, configNumber, configItem.Name, configItem.Path)
+			fmt.Fprintf(color.Output, " %v Using %v configuration #%v: %v (%v)\n", cyan("[i] INFO"), configItem.Type, opts.ConfigNumber, configItem.Name, configItem.Path)
 		}
 	} else {
 		// Burp ConfigLibrary configuration (v1.1.5)
-		if burpConfigName != "" {
-			configFile, err := FindBurpConfigByName(burpConfigName)
+		if opts.BurpConfigName != "" {
+			configFile, err := FindBurpConfigByName(opts.BurpConfigName)
 			if err != nil {
-				fmt.Fprintf(color.Output, "%v Error finding Burp config '%v': %v\n", red(" [-] ERROR:"), burpConfigName, err)
-				return ""
+				fmt.Fprintf(color.Output, "%v Error finding Burp config '%v': %v\n", red(" [-] ERROR:"), opts.BurpConfigName, err)
+				return "", nil
 			}
 			
 			configData, err := ioutil.ReadFile(configFile)
 			if err != nil {
 				fmt.Fprintf(color.Output, "%v Error reading Burp config file %v: %v\n", red(" [-] ERROR:"), configFile, err)
-				return ""
+				return "", nil
 			}
 			
 			configs = append(configs, map[string]interface{}{
 				"type":   "CustomConfiguration",
 				"config": string(configData),
 			})
-			fmt.Fprintf(color.Output, " %v Using Burp ConfigLibrary configuration: %v (%v)\n", cyan("[i] INFO"), burpConfigName, configFile)
+			fmt.Fprintf(color.Output, " %v Using Burp ConfigLibrary configuration: %v (%v)\n", cyan("[i] INFO"), opts.BurpConfigName, configFile)
 		}
 		
 		// Custom configuration file (v1.1.4)
-		if customConfigFile != "" {
-			configData, err := ioutil.ReadFile(customConfigFile)
+		if opts.CustomConfigFile != "" {
+			configData, err := ioutil.ReadFile(opts.CustomConfigFile)
 			if err != nil {
-				fmt.Fprintf(color.Output, "%v Error reading config file %v: %v\n", red(" [-] ERROR:"), customConfigFile, err)
-				return ""
+				fmt.Fprintf(color.Output, "%v Error reading config file %v: %v\n", red(" [-] ERROR:"), opts.CustomConfigFile, err)
+				return "", nil
 			}
 			
 			configs = append(configs, map[string]interface{}{
 				"type":   "CustomConfiguration",
 				"config": string(configData),
 			})
-			fmt.Fprintf(color.Output, " %v Using custom configuration file: %v\n", cyan("[i] INFO"), customConfigFile)
+			fmt.Fprintf(color.Output, " %v Using custom configuration file: %v\n", cyan("[i] INFO"), opts.CustomConfigFile)
 		}
 		
 		// Named configuration
-		if scanConfig != "" {
+		if opts.ScanConfig != "" {
 			configs = append(configs, map[string]interface{}{
 				"type": "NamedConfiguration",
-				"name": scanConfig,
+				"name": opts.ScanConfig,
 			})
-			fmt.Fprintf(color.Output, " %v Using scan configuration: %v\n", cyan("[i] INFO"), scanConfig)
+			fmt.Fprintf(color.Output, " %v Using scan configuration: %v\n", cyan("[i] INFO"), opts.ScanConfig)
 		}
 	}
 	
@@ -301,128 +360,59 @@ func ScanConfigAdvanced(target, port, urls, username, password, apikey, scanConf
 		scanRequest["scan_configurations"] = configs
 	}
 	
-	// Scope configuration - support both simple and advanced scope
-	if scopeInclude != "" || scopeExclude != "" {
-		scope := make(map[string]interface{})
-		
-		// v1.1.7: Advanced scope support
-		if advancedScope {
-			scope["type"] = "AdvancedScope"
-			fmt.Fprintf(color.Output, " %v Using advanced scope configuration\n", cyan("[i] INFO"))
-			
-			if scopeInclude != "" {
-				includes := strings.Split(scopeInclude, ",")
-				var includeRules []map[string]interface{}
-				for _, rule := range includes {
-					rule = strings.TrimSpace(rule)
-					if rule != "" {
-						// Parse advanced scope rule (protocol://host:port/path)
-						advRule := parseAdvancedScopeRule(rule)
-						includeRules = append(includeRules, advRule)
-					}
-				}
-				if len(includeRules) > 0 {
-					scope["include"] = includeRules
-					fmt.Fprintf(color.Output, " %v Advanced scope include: %v rules\n", cyan("[i] INFO"), len(includeRules))
-				}
-			}
-			
-			if scopeExclude != "" {
-				excludes := strings.Split(scopeExclude, ",")
-				var excludeRules []map[string]interface{}
-				for _, rule := range excludes {
-					rule = strings.TrimSpace(rule)
-					if rule != "" {
-						advRule := parseAdvancedScopeRule(rule)
-						excludeRules = append(excludeRules, advRule)
-					}
-				}
-				if len(excludeRules) > 0 {
-					scope["exclude"] = excludeRules
-					fmt.Fprintf(color.Output, " %v Advanced scope exclude: %v rules\n", cyan("[i] INFO"), len(excludeRules))
-				}
-			}
-		} else {
-			// Simple scope (existing functionality)
-			scope["type"] = "SimpleScope"
-			
-			if scopeInclude != "" {
-				includes := strings.Split(scopeInclude, ",")
-				var includeRules []map[string]string
-				for _, rule := range includes {
-					rule = strings.TrimSpace(rule)
-					if rule != "" {
-						includeRules = append(includeRules, map[string]string{"rule": rule})
-					}
-				}
-				if len(includeRules) > 0 {
-					scope["include"] = includeRules
-					fmt.Fprintf(color.Output, " %v Simple scope include: %v\n", cyan("[i] INFO"), scopeInclude)
-				}
-			}
-			
-			if scopeExclude != "" {
-				excludes := strings.Split(scopeExclude, ",")
-				var excludeRules []map[string]string
-				for _, rule := range excludes {
-					rule = strings.TrimSpace(rule)
-					if rule != "" {
-						excludeRules = append(excludeRules, map[string]string{"rule": rule})
-					}
-				}
-				if len(excludeRules) > 0 {
-					scope["exclude"] = excludeRules
-					fmt.Fprintf(color.Output, " %v Simple scope exclude: %v\n", cyan("[i] INFO"), scopeExclude)
-				}
-			}
+	// Scope configuration - support scope files, simple and advanced scope
+	if opts.ScopeFile != "" || opts.ScopeInclude != "" || opts.ScopeExclude != "" {
+		scope, err := buildScope(opts)
+		if err != nil {
+			fmt.Fprintf(color.Output, "%v %v\n", red(" [-] ERROR:"), err)
+			return "", nil
 		}
-		
 		scanRequest["scope"] = scope
 	}
 	
 	// Protocol option
-	if protocolOption != "" {
-		if protocolOption == "httpAndHttps" || protocolOption == "specified" {
-			scanRequest["protocol_option"] = protocolOption
-			fmt.Fprintf(color.Output, " %v Protocol option: %v\n", cyan("[i] INFO"), protocolOption)
+	if opts.ProtocolOption != "" {
+		if opts.ProtocolOption == "httpAndHttps" || opts.ProtocolOption == "specified" {
+			scanRequest["protocol_option"] = opts.ProtocolOption
+			fmt.Fprintf(color.Output, " %v Protocol option: %v\n", cyan("[i] INFO"), opts.ProtocolOption)
 		} else {
-			fmt.Fprintf(color.Output, " %v Invalid protocol option: %v, using default\n", yellow("[!] WARNING"), protocolOption)
+			fmt.Fprintf(color.Output, " %v Invalid protocol option: %v, using default\n", yellow("[!] WARNING"), opts.ProtocolOption)
 		}
 	}
 	
 	// v1.1.7: Resource pool support
-	if resourcePool != "" {
-		scanRequest["resource_pool"] = resourcePool
-		fmt.Fprintf(color.Output, " %v Resource pool: %v\n", cyan("[i] INFO"), resourcePool)
+	if opts.ResourcePool != "" {
+		scanRequest["resource_pool"] = opts.ResourcePool
+		fmt.Fprintf(color.Output, " %v Resource pool: %v\n", cyan("[i] INFO"), opts.ResourcePool)
 	}
 	
 	// v1.1.7: Callback URL support
-	if callbackURL != "" {
+	if opts.CallbackURL != "" {
 		callback := map[string]interface{}{
-			"url": callbackURL,
+			"url": opts.CallbackURL,
 		}
 		scanRequest["scan_callback"] = callback
-		fmt.Fprintf(color.Output, " %v Callback URL: %v\n", cyan("[i] INFO"), callbackURL)
+		fmt.Fprintf(color.Output, " %v Callback URL: %v\n", cyan("[i] INFO"), opts.CallbackURL)
 	}
 	
 	// Convert to JSON
 	jsonData, err := json.Marshal(scanRequest)
 	if err != nil {
 		fmt.Fprintf(color.Output, "%v Error creating scan request: %v\n", red(" [-] ERROR:"), err)
-		return ""
+		return "", nil
 	}
 	
 	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonData))
 	req.Header.Set("Content-Type", "application/json")
 	if err != nil {
 		fmt.Fprintf(color.Output, "%v Error creating request: %v\n", red(" [-] ERROR:"), err)
-		return ""
+		return "", nil
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
 		fmt.Fprintf(color.Output, "%v Error sending request: %v\n", red(" [-] ERROR:"), err)
-		return ""
+		return "", nil
 	}
 	
 	Location := resp.Header.Get("Location")
@@ -431,10 +421,10 @@ func ScanConfigAdvanced(target, port, urls, username, password, apikey, scanConf
 	if resp.StatusCode != 201 {
 		body, _ := ioutil.ReadAll(resp.Body)
 		fmt.Fprintf(color.Output, "%v Scan creation failed (Status: %v): %v\n", red(" [-] ERROR:"), resp.StatusCode, string(body))
-		return ""
+		return "", nil
 	}
 
-	return Location
+	return Location, scanRequest
 }
 
 // getBurpConfigLibraryPath returns the Burp Suite ConfigLibrary path for the current OS and user
@@ -0,0 +1,362 @@
+package configure
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/joanbono/color"
+	"github.com/tidwall/gjson"
+)
+
+// ScopeDefinition is a Burp scan scope assembled from flags and scope files
+type ScopeDefinition struct {
+	Type    string // "SimpleScope" or "AdvancedScope"
+	Include []map[string]interface{}
+	Exclude []map[string]interface{}
+}
+
+// ToRequest converts the scope into the "scope" object of a scan request
+func (sd *ScopeDefinition) ToRequest() map[string]interface{} {
+	scope := map[string]interface{}{
+		"type": sd.Type,
+	}
+	if len(sd.Include) > 0 {
+		scope["include"] = sd.Include
+	}
+	if len(sd.Exclude) > 0 {
+		scope["exclude"] = sd.Exclude
+	}
+	return scope
+}
+
+// webAssetTypes lists bug-bounty asset types that can be scanned by Burp
+var webAssetTypes = map[string]bool{
+	"":           true,
+	"url":        true,
+	"wildcard":   true,
+	"domain":     true,
+	"cidr":       true,
+	"ip_address": true,
+	"api":        true,
+	"website":    true,
+	"web":        true,
+}
+
+// LoadScopeFile parses a Burp project options JSON export or a HackerOne/Bugcrowd
+// CSV scope export into a scope definition
+func LoadScopeFile(fileName string) (*ScopeDefinition, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scope file %v: %v", fileName, err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return parseBurpScope(trimmed)
+	}
+	return parseBountyScopeCSV(trimmed)
+}
+
+// parseBurpScope reads target.scope from Burp's exported project options
+func parseBurpScope(data []byte) (*ScopeDefinition, error) {
+	if !gjson.ValidBytes(data) {
+		return nil, fmt.Errorf("scope file is not valid JSON")
+	}
+
+	scope := gjson.GetBytes(data, "target.scope")
+	if !scope.Exists() {
+		// Also accept a bare {"scope": {...}} or the scope object itself
+		scope = gjson.GetBytes(data, "scope")
+		if !scope.Exists() {
+			scope = gjson.ParseBytes(data)
+		}
+	}
+	if !scope.Get("include").Exists() && !scope.Get("exclude").Exists() {
+		return nil, fmt.Errorf("no target.scope include/exclude rules found in scope file")
+	}
+
+	advanced := scope.Get("advanced_mode").Bool()
+	sd := &ScopeDefinition{Type: "SimpleScope"}
+	if advanced {
+		sd.Type = "AdvancedScope"
+	}
+
+	convert := func(entry gjson.Result) map[string]interface{} {
+		// Anything but a rule object would become a rule matching every host
+		if !entry.IsObject() {
+			return nil
+		}
+		if enabled := entry.Get("enabled"); enabled.Exists() && !enabled.Bool() {
+			return nil
+		}
+		if !advanced {
+			prefix := entry.Get("prefix").String()
+			if prefix == "" {
+				return nil
+			}
+			return map[string]interface{}{"rule": prefix}
+		}
+
+		rule := map[string]interface{}{
+			"protocol": "any",
+		}
+		if protocol := strings.ToLower(entry.Get("protocol").String()); protocol == "http" || protocol == "https" {
+			rule["protocol"] = protocol
+		}
+		if host := entry.Get("host").String(); host != "" {
+			rule["host_or_ip_range"] = host
+		}
+		if port := entry.Get("port").String(); port != "" {
+			rule["port"] = port
+		}
+		if file := entry.Get("file").String(); file != "" {
+			rule["file"] = file
+		}
+		return rule
+	}
+
+	for _, entry := range scope.Get("include").Array() {
+		if rule := convert(entry); rule != nil {
+			sd.Include = append(sd.Include, rule)
+		}
+	}
+	for _, entry := range scope.Get("exclude").Array() {
+		if rule := convert(entry); rule != nil {
+			sd.Exclude = append(sd.Exclude, rule)
+		}
+	}
+
+	return sd, nil
+}
+
+// parseBountyScopeCSV reads HackerOne and Bugcrowd scope CSV exports
+func parseBountyScopeCSV(data []byte) (*ScopeDefinition, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scope CSV header: %v", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	findColumn := func(names ...string) int {
+		for _, name := range names {
+			if idx, ok := columns[name]; ok {
+				return idx
+			}
+		}
+		return -1
+	}
+
+	identifierCol := findColumn("identifier", "target", "uri", "url", "asset", "name")
+	if identifierCol == -1 {
+		return nil, fmt.Errorf("scope CSV has no identifier/target column")
+	}
+	typeCol := findColumn("asset_type", "category", "type")
+	scopeCol := findColumn("eligible_for_submission", "in_scope", "in scope", "scope")
+
+	sd := &ScopeDefinition{Type: "AdvancedScope"}
+	skipped, malformed := 0, 0
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			// The reader continues with the next line
+			malformed++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse scope CSV: %v", err)
+		}
+		if identifierCol >= len(record) {
+			continue
+		}
+
+		identifier := strings.TrimSpace(record[identifierCol])
+		if identifier == "" {
+			continue
+		}
+
+		if typeCol != -1 && typeCol < len(record) {
+			if !webAssetTypes[strings.ToLower(strings.TrimSpace(record[typeCol]))] {
+				skipped++
+				continue
+			}
+		}
+
+		inScope := true
+		if scopeCol != -1 && scopeCol < len(record) {
+			inScope = isInScopeValue(record[scopeCol])
+		}
+
+		// Identifiers may hold several comma-separated assets
+		for _, asset := range strings.Split(identifier, ",") {
+			asset = strings.TrimSpace(asset)
+			if asset == "" {
+				continue
+			}
+			rule := scopeRuleFromAsset(asset)
+			if inScope {
+				sd.Include = append(sd.Include, rule)
+			} else {
+				sd.Exclude = append(sd.Exclude, rule)
+			}
+		}
+	}
+
+	if skipped > 0 {
+		fmt.Fprintf(color.Output, " %v Skipped %v non-web assets from scope file\n", yellow("[!] WARNING"), skipped)
+	}
+	if malformed > 0 {
+		fmt.Fprintf(color.Output, " %v Skipped %v malformed lines of scope file\n", yellow("[!] WARNING"), malformed)
+	}
+	if len(sd.Include) == 0 && len(sd.Exclude) == 0 {
+		return nil, fmt.Errorf("no web assets found in scope CSV")
+	}
+
+	return sd, nil
+}
+
+// isInScopeValue interprets the in-scope column of bug-bounty exports
+func isInScopeValue(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "false", "no", "0", "out", "out of scope", "out-of-scope", "oos":
+		return false
+	}
+	return true
+}
+
+// scopeRuleFromAsset converts a bug-bounty asset into an advanced scope rule
+func scopeRuleFromAsset(asset string) map[string]interface{} {
+	rule := map[string]interface{}{
+		"protocol": "any",
+	}
+
+	// CIDRs and IP ranges are accepted by Burp as-is
+	if _, _, err := net.ParseCIDR(asset); err == nil || isIPRange(asset) || net.ParseIP(asset) != nil {
+		rule["host_or_ip_range"] = asset
+		return rule
+	}
+
+	if strings.Contains(asset, "://") {
+		// Wildcards are not valid URL hosts, so protect them while parsing
+		u, err := url.Parse(strings.Replace(asset, "*", "wildcard-placeholder", -1))
+		if err == nil && u.Host != "" {
+			if u.Scheme == "http" || u.Scheme == "https" {
+				rule["protocol"] = u.Scheme
+			}
+			rule["host_or_ip_range"] = hostPatternToRegex(strings.Replace(u.Hostname(), "wildcard-placeholder", "*", -1))
+			if u.Port() != "" {
+				rule["port"] = "^" + u.Port() + "$"
+			}
+			path := strings.Replace(u.Path, "wildcard-placeholder", "*", -1)
+			if path != "" && path != "/" {
+				rule["file"] = "^" + wildcardToRegex(path) + ".*"
+			}
+			return rule
+		}
+	}
+
+	host := asset
+	if idx := strings.Index(host, "/"); idx != -1 {
+		rule["file"] = "^" + wildcardToRegex(host[idx:]) + ".*"
+		host = host[:idx]
+	}
+	rule["host_or_ip_range"] = hostPatternToRegex(host)
+	return rule
+}
+
+// hostPatternToRegex converts hosts such as *.example.com into Burp host regexes
+func hostPatternToRegex(host string) string {
+	if strings.HasPrefix(host, "*.") {
+		return `^(.*\.)?` + regexp.QuoteMeta(strings.TrimPrefix(host, "*.")) + "$"
+	}
+	return "^" + wildcardToRegex(host) + "$"
+}
+
+// wildcardToRegex quotes a pattern and turns * into .*
+func wildcardToRegex(pattern string) string {
+	return strings.Replace(regexp.QuoteMeta(pattern), `\*`, ".*", -1)
+}
+
+// isIPRange reports whether value looks like 10.0.0.1-10.0.0.50
+func isIPRange(value string) bool {
+	parts := strings.Split(value, "-")
+	return len(parts) == 2 && net.ParseIP(strings.TrimSpace(parts[0])) != nil && net.ParseIP(strings.TrimSpace(parts[1])) != nil
+}
+
+// buildScope assembles the scan request scope from a scope file and -si/-se rules
+func buildScope(opts ScanOptions) (map[string]interface{}, error) {
+	sd := &ScopeDefinition{Type: "SimpleScope"}
+
+	if opts.ScopeFile != "" {
+		fileScope, err := LoadScopeFile(opts.ScopeFile)
+		if err != nil {
+			return nil, err
+		}
+		sd = fileScope
+		fmt.Fprintf(color.Output, " %v Loaded %v scope from %v: %v include, %v exclude rules\n", cyan("[i] INFO"), sd.Type, opts.ScopeFile, len(sd.Include), len(sd.Exclude))
+	} else if opts.AdvancedScope {
+		// v1.1.7: Advanced scope support
+		sd.Type = "AdvancedScope"
+		fmt.Fprintf(color.Output, " %v Using advanced scope configuration\n", cyan("[i] INFO"))
+	}
+
+	// Command line rules are added to whatever the scope file provided
+	convert := func(rule string) map[string]interface{} {
+		if sd.Type == "AdvancedScope" {
+			// Parse advanced scope rule (protocol://host:port/path)
+			return parseAdvancedScopeRule(rule)
+		}
+		return map[string]interface{}{"rule": rule}
+	}
+
+	added := func(list string) []map[string]interface{} {
+		var rules []map[string]interface{}
+		for _, rule := range strings.Split(list, ",") {
+			rule = strings.TrimSpace(rule)
+			if rule != "" {
+				rules = append(rules, convert(rule))
+			}
+		}
+		return rules
+	}
+
+	if includeRules := added(opts.ScopeInclude); len(includeRules) > 0 {
+		sd.Include = append(sd.Include, includeRules...)
+		if sd.Type == "AdvancedScope" {
+			fmt.Fprintf(color.Output, " %v Advanced scope include: %v rules\n", cyan("[i] INFO"), len(includeRules))
+		} else {
+			fmt.Fprintf(color.Output, " %v Simple scope include: %v\n", cyan("[i] INFO"), opts.ScopeInclude)
+		}
+	}
+
+	if excludeRules := added(opts.ScopeExclude); len(excludeRules) > 0 {
+		sd.Exclude = append(sd.Exclude, excludeRules...)
+		if sd.Type == "AdvancedScope" {
+			fmt.Fprintf(color.Output, " %v Advanced scope exclude: %v rules\n", cyan("[i] INFO"), len(excludeRules))
+		} else {
+			fmt.Fprintf(color.Output, " %v Simple scope exclude: %v\n", cyan("[i] INFO"), opts.ScopeExclude)
+		}
+	}
+
+	return sd.ToRequest(), nil
+}
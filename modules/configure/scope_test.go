@@ -0,0 +1,137 @@
+package configure
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Burp project options export with a disabled rule and an entry that is not
+// a rule object
+const burpScopeFixture = `{
+    "target":{
+        "scope":{
+            "advanced_mode":true,
+            "exclude":[
+                {
+                    "enabled":true,
+                    "file":"^/logout.*",
+                    "host":"^www\\.example\\.com$",
+                    "port":"^443$",
+                    "protocol":"https"
+                }
+            ],
+            "include":[
+                {
+                    "enabled":true,
+                    "host":"^.*\\.example\\.com$",
+                    "protocol":"any"
+                },
+                {
+                    "enabled":false,
+                    "host":"^staging\\.example\\.com$",
+                    "protocol":"any"
+                },
+                "^.*\\.example\\.org$"
+            ]
+        }
+    }
+}
+`
+
+// HackerOne scope export with a non-web asset, an out-of-scope asset and a
+// line with a bare quote
+const hackerOneFixture = `identifier,asset_type,instruction,eligible_for_bounty,eligible_for_submission,availability_requirement,confidentiality_requirement,integrity_requirement,max_severity,system_tags,created_at,updated_at
+*.example.com,WILDCARD,,true,true,,,,critical,,2024-01-10 10:00:00 UTC,2024-01-10 10:00:00 UTC
+https://app.example.com/api,URL,,true,true,,,,critical,,2024-01-10 10:00:00 UTC,2024-01-10 10:00:00 UTC
+shop.example.com,URL,Log in with the "test" account,true,true,,,,high,,2024-01-10 10:00:00 UTC,2024-01-10 10:00:00 UTC
+com.example.android,GOOGLE_PLAY_APP_ID,,true,true,,,,high,,2024-01-10 10:00:00 UTC,2024-01-10 10:00:00 UTC
+legacy.example.com,URL,Decommissioned,false,false,,,,none,,2024-01-10 10:00:00 UTC,2024-01-10 10:00:00 UTC
+10.10.0.0/24,CIDR,,true,true,,,,critical,,2024-01-10 10:00:00 UTC,2024-01-10 10:00:00 UTC
+`
+
+func TestLoadScopeFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *ScopeDefinition
+		wantErr bool
+	}{
+		{
+			name:    "burp project options",
+			content: burpScopeFixture,
+			want: &ScopeDefinition{
+				Type: "AdvancedScope",
+				Include: []map[string]interface{}{
+					{"protocol": "any", "host_or_ip_range": `^.*\.example\.com$`},
+				},
+				Exclude: []map[string]interface{}{
+					{"protocol": "https", "host_or_ip_range": `^www\.example\.com$`, "port": "^443$", "file": "^/logout.*"},
+				},
+			},
+		},
+		{
+			name:    "burp simple scope",
+			content: `{"target":{"scope":{"advanced_mode":false,"include":[{"enabled":true,"prefix":"https://www.example.com/"},{"enabled":true}]}}}`,
+			want: &ScopeDefinition{
+				Type:    "SimpleScope",
+				Include: []map[string]interface{}{{"rule": "https://www.example.com/"}},
+			},
+		},
+		{
+			name:    "hackerone csv",
+			content: hackerOneFixture,
+			want: &ScopeDefinition{
+				Type: "AdvancedScope",
+				Include: []map[string]interface{}{
+					{"protocol": "any", "host_or_ip_range": `^(.*\.)?example\.com$`},
+					{"protocol": "https", "host_or_ip_range": `^app\.example\.com$`, "file": "^/api.*"},
+					{"protocol": "any", "host_or_ip_range": "10.10.0.0/24"},
+				},
+				Exclude: []map[string]interface{}{
+					{"protocol": "any", "host_or_ip_range": `^legacy\.example\.com$`},
+				},
+			},
+		},
+		{
+			name:    "bugcrowd csv",
+			content: "name,category,in_scope\nhttps://*.example.net,website,true\nexample.net/admin/*,api,out of scope\n",
+			want: &ScopeDefinition{
+				Type: "AdvancedScope",
+				Include: []map[string]interface{}{
+					{"protocol": "https", "host_or_ip_range": `^(.*\.)?example\.net$`},
+				},
+				Exclude: []map[string]interface{}{
+					{"protocol": "any", "host_or_ip_range": `^example\.net$`, "file": "^/admin/.*.*"},
+				},
+			},
+		},
+		{
+			name:    "truncated json",
+			content: burpScopeFixture[:len(burpScopeFixture)/2],
+			wantErr: true,
+		},
+		{
+			name:    "csv without identifiers",
+			content: "asset_type,in_scope\nURL,true\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "scope")
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+
+		got, err := LoadScopeFile(path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: LoadScopeFile error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: LoadScopeFile = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package har
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// staticExtensions are file extensions that never need to be audited
+var staticExtensions = map[string]bool{
+	".js": true, ".mjs": true, ".css": true, ".map": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".ico": true, ".webp": true, ".bmp": true, ".avif": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true,
+	".mp3": true, ".mp4": true, ".webm": true, ".ogg": true, ".wav": true, ".avi": true, ".mov": true,
+}
+
+// staticMIMEPrefixes are response MIME types of static assets
+var staticMIMEPrefixes = []string{
+	"image/", "font/", "audio/", "video/",
+	"text/css", "text/javascript", "application/javascript", "application/x-javascript",
+	"application/font", "application/x-font",
+}
+
+// harFile is the subset of the HAR 1.2 format needed for seeding
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				Method string `json:"method"`
+				URL    string `json:"url"`
+			} `json:"request"`
+			Response struct {
+				Content struct {
+					MimeType string `json:"mimeType"`
+				} `json:"content"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// Options controls which HAR entries become seed URLs
+type Options struct {
+	// Hosts limits the seeds to matching hosts (supports *.example.com)
+	Hosts []string
+	// KeepStatic keeps static assets such as scripts, styles and images
+	KeepStatic bool
+}
+
+// SeedSet holds the seed URLs extracted from a HAR capture
+type SeedSet struct {
+	URLs    []string
+	Origins []string
+	Total   int
+	Static  int
+	Skipped int
+}
+
+// ScopeRules returns URL prefixes for every origin seen in the capture
+func (ss *SeedSet) ScopeRules() []string {
+	var rules []string
+	for _, origin := range ss.Origins {
+		rules = append(rules, origin+"/")
+	}
+	return rules
+}
+
+// ParseFile extracts unique, in-scope request URLs from a HAR file. URLs are
+// de-duplicated by scheme, host, path and the sorted set of parameter names.
+func ParseFile(fileName string, opts Options) (*SeedSet, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HAR file %v: %v", fileName, err)
+	}
+
+	var capture harFile
+	if err := json.Unmarshal(data, &capture); err != nil {
+		return nil, fmt.Errorf("failed to parse HAR file: %v", err)
+	}
+
+	ss := &SeedSet{}
+	seen := make(map[string]bool)
+	origins := make(map[string]bool)
+
+	for _, entry := range capture.Log.Entries {
+		ss.Total++
+
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			ss.Skipped++
+			continue
+		}
+
+		if len(opts.Hosts) > 0 && !matchesHost(u.Hostname(), opts.Hosts) {
+			ss.Skipped++
+			continue
+		}
+
+		if !opts.KeepStatic && isStatic(u, entry.Response.Content.MimeType) {
+			ss.Static++
+			continue
+		}
+
+		key := dedupKey(u)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		u.Fragment = ""
+		ss.URLs = append(ss.URLs, u.String())
+
+		origin := u.Scheme + "://" + u.Host
+		if !origins[origin] {
+			origins[origin] = true
+			ss.Origins = append(ss.Origins, origin)
+		}
+	}
+
+	if len(ss.URLs) == 0 {
+		return nil, fmt.Errorf("no in-scope request URLs found in %v", fileName)
+	}
+
+	return ss, nil
+}
+
+// dedupKey identifies a request by location and parameter names, ignoring values
+func dedupKey(u *url.URL) string {
+	var names []string
+	for name := range u.Query() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.ToLower(u.Scheme+"://"+u.Host) + u.EscapedPath() + "?" + strings.Join(names, "&")
+}
+
+// isStatic reports whether a request targets a static asset
+func isStatic(u *url.URL, mimeType string) bool {
+	if staticExtensions[strings.ToLower(path.Ext(u.Path))] {
+		return true
+	}
+
+	mimeType = strings.ToLower(mimeType)
+	for _, prefix := range staticMIMEPrefixes {
+		if strings.HasPrefix(mimeType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesHost checks a hostname against host patterns
+func matchesHost(host string, patterns []string) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if strings.HasPrefix(pattern, "*.") {
+			suffix := strings.TrimPrefix(pattern, "*")
+			if strings.HasSuffix(host, suffix) || host == strings.TrimPrefix(suffix, ".") {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joanbono/color"
+)
+
+var red = color.New(color.Bold, color.FgRed).SprintfFunc()
+var cyan = color.New(color.Bold, color.FgCyan).SprintfFunc()
+var green = color.New(color.Bold, color.FgGreen).SprintfFunc()
+
+// Hook events
+const (
+	EventPreScan  = "pre-scan"
+	EventPostScan = "post-scan"
+)
+
+// DefaultTimeout is used when no hook timeout is configured
+const DefaultTimeout = 60 * time.Second
+
+// Payload describes a scan for a hook command. It is written as JSON to the
+// hook's stdin and mirrored in BURP_CLI_* environment variables.
+type Payload struct {
+	Event          string         `json:"event"`
+	ScanID         string         `json:"scan_id,omitempty"`
+	URL            string         `json:"url"`
+	Status         string         `json:"status"`
+	JSONExport     string         `json:"json_export,omitempty"`
+	HTMLExport     string         `json:"html_export,omitempty"`
+	SeverityCounts map[string]int `json:"severity_counts,omitempty"`
+	Timestamp      time.Time      `json:"timestamp"`
+}
+
+// Result holds the outcome of a hook execution
+type Result struct {
+	ExitCode int
+	Duration time.Duration
+	TimedOut bool
+}
+
+// Run executes a hook command with the given payload. The command runs through
+// the shell (sh -c, or cmd /C on Windows), so quoting, paths with spaces and
+// extra arguments work as on the command line. A non-nil error is returned when the hook cannot start, times out or exits
+// with a non-zero code.
+func Run(command string, payload Payload, timeout time.Duration) (Result, error) {
+	var result Result
+
+	if strings.TrimSpace(command) == "" {
+		return result, errors.New("empty hook command")
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if payload.Timestamp.IsZero() {
+		payload.Timestamp = time.Now()
+	}
+
+	stdin, err := json.Marshal(payload)
+	if err != nil {
+		return result, fmt.Errorf("failed to marshal hook payload: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, command)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), payloadEnv(payload)...)
+
+	start := time.Now()
+	err = cmd.Run()
+	result.Duration = time.Since(start)
+
+	if ctx.Err() == context.DeadlineExceeded {
+		result.TimedOut = true
+		result.ExitCode = -1
+		return result, fmt.Errorf("hook timed out after %v", timeout)
+	}
+
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.ExitCode = exitErr.ExitCode()
+			return result, fmt.Errorf("hook exited with code %d", result.ExitCode)
+		}
+		result.ExitCode = -1
+		return result, fmt.Errorf("failed to run hook: %v", err)
+	}
+
+	return result, nil
+}
+
+// RunLogged executes a hook and prints its exit code and duration. It returns
+// false when the hook failed.
+func RunLogged(command string, payload Payload, timeout time.Duration) bool {
+	fmt.Fprintf(color.Output, "%v Running %v hook: %v\n", cyan(" [i] INFO:"), payload.Event, command)
+
+	result, err := Run(command, payload, timeout)
+	if err != nil {
+		fmt.Fprintf(color.Output, "%v %v hook failed (exit code %d, %v): %v\n", red(" [-] ERROR:"), payload.Event, result.ExitCode, result.Duration.Round(time.Millisecond), err)
+		return false
+	}
+
+	fmt.Fprintf(color.Output, "%v %v hook finished (exit code %d, %v)\n", green(" [+] SUCCESS:"), payload.Event, result.ExitCode, result.Duration.Round(time.Millisecond))
+	return true
+}
+
+// shellCommand runs command through the platform shell
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// payloadEnv converts a payload into BURP_CLI_* environment variables
+func payloadEnv(payload Payload) []string {
+	env := []string{
+		"BURP_CLI_EVENT=" + payload.Event,
+		"BURP_CLI_SCAN_ID=" + payload.ScanID,
+		"BURP_CLI_TARGET_URL=" + payload.URL,
+		"BURP_CLI_SCAN_STATUS=" + payload.Status,
+		"BURP_CLI_JSON_EXPORT=" + payload.JSONExport,
+		"BURP_CLI_HTML_EXPORT=" + payload.HTMLExport,
+	}
+
+	for _, severity := range []string{"high", "medium", "low", "info"} {
+		env = append(env, "BURP_CLI_ISSUES_"+strings.ToUpper(severity)+"="+strconv.Itoa(payload.SeverityCounts[severity]))
+	}
+
+	return env
+}
@@ -0,0 +1,320 @@
+package importer
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxExpandedHosts caps range expansion to avoid accidental /8 scans
+const MaxExpandedHosts = 65536
+
+// DefaultMaxCandidates caps the unprobed candidate URLs a single run submits,
+// so a /16 with a port list can't silently become tens of thousands of scans
+const DefaultMaxCandidates = 1024
+
+// DefaultPorts are used when a range entry has no port list
+var DefaultPorts = []int{80, 443}
+
+// ParsePorts parses a port list such as "80,443,8080-8090"
+func ParsePorts(value string) ([]int, error) {
+	var ports []int
+	seen := make(map[int]bool)
+
+	add := func(port int) error {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("port out of range: %d", port)
+		}
+		if !seen[port] {
+			seen[port] = true
+			ports = append(ports, port)
+		}
+		return nil
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if bounds := strings.SplitN(part, "-", 2); len(bounds) == 2 {
+			low, err1 := strconv.Atoi(strings.TrimSpace(bounds[0]))
+			high, err2 := strconv.Atoi(strings.TrimSpace(bounds[1]))
+			if err1 != nil || err2 != nil || low > high {
+				return nil, fmt.Errorf("invalid port range: %s", part)
+			}
+			for port := low; port <= high; port++ {
+				if err := add(port); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		port, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port: %s", part)
+		}
+		if err := add(port); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("empty port list")
+	}
+	return ports, nil
+}
+
+// IsRangeEntry reports whether a list entry needs expansion: a CIDR or IP
+// range, or an IP followed by a port list. Bare IPs and host:port entries are
+// left for Burp to resolve as before.
+func IsRangeEntry(entry string) bool {
+	hosts, ports := splitRangeEntry(entry)
+	if strings.Contains(hosts, "://") {
+		return false
+	}
+	if _, _, err := net.ParseCIDR(hosts); err == nil {
+		// Oversized CIDRs still count so the size limit is reported
+		return true
+	}
+	if _, err := ExpandHosts(hosts); err != nil {
+		return false
+	}
+	if net.ParseIP(hosts) == nil {
+		return true
+	}
+	return strings.ContainsAny(ports, ",-") || len(strings.Fields(entry)) == 2
+}
+
+// splitRangeEntry separates "10.0.0.0/24 80,443" or "10.0.0.0/24:80,443"
+func splitRangeEntry(entry string) (hosts, ports string) {
+	entry = strings.TrimSpace(entry)
+	if fields := strings.Fields(entry); len(fields) == 2 {
+		return fields[0], fields[1]
+	}
+	// IPv6 addresses contain colons, so only split IPv4 style entries
+	if idx := strings.LastIndex(entry, ":"); idx != -1 && strings.Count(entry, ":") == 1 {
+		return entry[:idx], entry[idx+1:]
+	}
+	return entry, ""
+}
+
+// ExpandHosts expands a CIDR, a range (10.0.0.1-10.0.0.20 or 10.0.0.1-20) or
+// a single IP into individual addresses
+func ExpandHosts(spec string) ([]string, error) {
+	spec = strings.TrimSpace(spec)
+
+	if ip := net.ParseIP(spec); ip != nil {
+		return []string{ip.String()}, nil
+	}
+
+	if _, network, err := net.ParseCIDR(spec); err == nil {
+		ones, bits := network.Mask.Size()
+		if bits != 32 {
+			return nil, fmt.Errorf("only IPv4 CIDRs can be expanded: %s", spec)
+		}
+		size := uint64(1) << uint(bits-ones)
+		if size > MaxExpandedHosts {
+			return nil, fmt.Errorf("%s expands to %d hosts (limit %d)", spec, size, MaxExpandedHosts)
+		}
+		start := binary.BigEndian.Uint32(network.IP.To4())
+		first, last := start, start+uint32(size)-1
+		// Skip network and broadcast addresses of regular subnets
+		if size > 2 {
+			first++
+			last--
+		}
+		return ipv4Range(first, last), nil
+	}
+
+	if parts := strings.SplitN(spec, "-", 2); len(parts) == 2 {
+		startIP := net.ParseIP(strings.TrimSpace(parts[0])).To4()
+		if startIP == nil {
+			return nil, fmt.Errorf("invalid range start: %s", parts[0])
+		}
+
+		endPart := strings.TrimSpace(parts[1])
+		endIP := net.ParseIP(endPart).To4()
+		if endIP == nil {
+			// Short form: 10.0.0.1-50 replaces the last octet
+			octet, err := strconv.Atoi(endPart)
+			if err != nil || octet < 0 || octet > 255 {
+				return nil, fmt.Errorf("invalid range end: %s", endPart)
+			}
+			endIP = net.IPv4(startIP[0], startIP[1], startIP[2], byte(octet)).To4()
+		}
+
+		first := binary.BigEndian.Uint32(startIP)
+		last := binary.BigEndian.Uint32(endIP)
+		if first > last {
+			return nil, fmt.Errorf("range start is after range end: %s", spec)
+		}
+		if uint64(last-first)+1 > MaxExpandedHosts {
+			return nil, fmt.Errorf("%s expands to more than %d hosts", spec, MaxExpandedHosts)
+		}
+		return ipv4Range(first, last), nil
+	}
+
+	return nil, fmt.Errorf("not an IP, CIDR or IP range: %s", spec)
+}
+
+// ipv4Range lists every address between first and last inclusive
+func ipv4Range(first, last uint32) []string {
+	hosts := make([]string, 0, last-first+1)
+	for n := uint64(first); n <= uint64(last); n++ {
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, uint32(n))
+		hosts = append(hosts, ip.String())
+	}
+	return hosts
+}
+
+// ExpandEntry turns a range entry into candidate targets. Ports listed in the
+// entry take precedence over defaultPorts. Ports without a known scheme yield
+// both an http and an https candidate.
+func ExpandEntry(entry string, defaultPorts []int) ([]Target, error) {
+	hostSpec, portSpec := splitRangeEntry(entry)
+
+	hosts, err := ExpandHosts(hostSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	ports := defaultPorts
+	if portSpec != "" {
+		if ports, err = ParsePorts(portSpec); err != nil {
+			return nil, err
+		}
+	}
+	if len(ports) == 0 {
+		ports = DefaultPorts
+	}
+
+	var targets []Target
+	for _, host := range hosts {
+		for _, port := range ports {
+			schemes := []string{"http", "https"}
+			if scheme, ok := webPorts[port]; ok {
+				schemes = []string{scheme}
+			}
+			for _, scheme := range schemes {
+				targets = append(targets, Target{
+					URL:       buildURL(scheme, host, port),
+					Host:      host,
+					Port:      port,
+					Scheme:    scheme,
+					Candidate: true,
+				})
+			}
+		}
+	}
+	return targets, nil
+}
+
+// ProbeOptions controls the live-service pre-probe
+type ProbeOptions struct {
+	Timeout     time.Duration
+	Concurrency int
+}
+
+// Probe keeps only candidate targets that answer TLS or HTTP. The scheme is
+// decided by the probe, so each live host:port is returned once.
+// Targets that are not candidates are returned unchanged.
+func Probe(targets []Target, opts ProbeOptions) []Target {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Second
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 50
+	}
+
+	var result []Target
+	type endpoint struct {
+		host string
+		port int
+	}
+	var endpoints []endpoint
+	seen := make(map[endpoint]bool)
+
+	for _, target := range targets {
+		if !target.Candidate {
+			result = append(result, target)
+			continue
+		}
+		key := endpoint{target.Host, target.Port}
+		if !seen[key] {
+			seen[key] = true
+			endpoints = append(endpoints, key)
+		}
+	}
+
+	live := make([]Target, len(endpoints))
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, ep := range endpoints {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, ep endpoint) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			scheme, ok := probeScheme(ep.host, ep.port, opts.Timeout)
+			if ok {
+				live[i] = Target{
+					URL:    buildURL(scheme, ep.host, ep.port),
+					Host:   ep.host,
+					Port:   ep.port,
+					Scheme: scheme,
+				}
+			}
+		}(i, ep)
+	}
+	wg.Wait()
+
+	for _, target := range live {
+		if target.URL != "" {
+			result = append(result, target)
+		}
+	}
+	return result
+}
+
+// probeScheme returns https when host:port completes a TLS handshake and http
+// when it answers a plain HTTP request. Other services are not web services.
+func probeScheme(host string, port int, timeout time.Duration) (string, bool) {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return "", false
+	}
+	conn.Close()
+
+	dialer := &net.Dialer{Timeout: timeout}
+	tlsConn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{InsecureSkipVerify: true})
+	if err == nil {
+		tlsConn.Close()
+		return "https", true
+	}
+
+	conn, err = net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return "", false
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	fmt.Fprintf(conn, "HEAD / HTTP/1.0\r\nHost: %s\r\n\r\n", host)
+	reply := make([]byte, 5)
+	if _, err := io.ReadFull(conn, reply); err != nil || string(reply) != "HTTP/" {
+		return "", false
+	}
+	return "http", true
+}
@@ -0,0 +1,668 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"burp-cli/modules/nmap"
+)
+
+// Supported input formats
+const (
+	FormatList        = "list"
+	FormatHttpx       = "httpx"
+	FormatNaabu       = "naabu"
+	FormatMasscanXML  = "masscan-xml"
+	FormatMasscanJSON = "masscan-json"
+	FormatNmapXML     = "nmap-xml"
+)
+
+// webPorts maps well-known web ports to the scheme used to reach them
+var webPorts = map[int]string{
+	80: "http", 81: "http", 591: "http", 3000: "http", 3001: "http", 5000: "http", 5001: "http",
+	7001: "http", 8000: "http", 8001: "http", 8008: "http", 8080: "http", 8081: "http", 8082: "http",
+	8088: "http", 8800: "http", 8880: "http", 8888: "http", 9000: "http", 9080: "http", 9090: "http",
+	443: "https", 593: "https", 4443: "https", 7443: "https", 8443: "https", 8444: "https", 9443: "https", 10443: "https",
+}
+
+// Target is a single web service found in a recon output
+type Target struct {
+	URL        string
+	Host       string
+	Port       int
+	Scheme     string
+	StatusCode int
+	Title      string
+	// Candidate marks URLs guessed from a range expansion that were not
+	// confirmed by a recon tool
+	Candidate bool
+}
+
+// Options controls how a file is imported. Status and title filters only
+// apply to formats that carry HTTP information (httpx). Ports are used for
+// CIDR and IP range entries of plain lists that carry no port list. With a
+// Probe, only range candidates that answer as web services are kept.
+type Options struct {
+	StatusCodes []int
+	Title       *regexp.Regexp
+	Ports       []int
+	Probe       *ProbeOptions
+}
+
+// Result holds the imported targets and statistics about the input
+type Result struct {
+	Format   string
+	Targets  []Target
+	Filtered int
+	Skipped  int
+	Expanded int
+	// Live counts range candidates that answered the probe
+	Live int
+	// Malformed counts lines of a recon output that could not be parsed,
+	// e.g. the last line of an interrupted run
+	Malformed int
+}
+
+// URLs returns the unique target URLs
+func (r *Result) URLs() []string {
+	seen := make(map[string]bool)
+	var urls []string
+	for _, target := range r.Targets {
+		if !seen[target.URL] {
+			seen[target.URL] = true
+			urls = append(urls, target.URL)
+		}
+	}
+	return urls
+}
+
+// ParseStatusCodes parses a comma-separated list such as "200,301-302"
+func ParseStatusCodes(value string) ([]int, error) {
+	var codes []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if bounds := strings.SplitN(part, "-", 2); len(bounds) == 2 {
+			low, err1 := strconv.Atoi(bounds[0])
+			high, err2 := strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil || low > high {
+				return nil, fmt.Errorf("invalid status code range: %s", part)
+			}
+			for code := low; code <= high; code++ {
+				codes = append(codes, code)
+			}
+			continue
+		}
+		code, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code: %s", part)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// DetectFormat inspects file content and returns the recon tool format
+func DetectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return FormatList
+	}
+
+	if trimmed[0] == '<' {
+		if bytes.Contains(trimmed[:min(len(trimmed), 4096)], []byte(`scanner="masscan"`)) {
+			return FormatMasscanXML
+		}
+		if bytes.Contains(trimmed[:min(len(trimmed), 4096)], []byte("<nmaprun")) {
+			return FormatNmapXML
+		}
+		return FormatList
+	}
+
+	// masscan writes a JSON array, usually with a trailing comma. Lists can
+	// start with '[' too, like "[::1]:8080".
+	if trimmed[0] == '[' && isMasscanJSON(trimmed) {
+		return FormatMasscanJSON
+	}
+
+	if trimmed[0] == '{' {
+		firstLine := trimmed
+		if idx := bytes.IndexByte(trimmed, '\n'); idx != -1 {
+			firstLine = trimmed[:idx]
+		}
+		var probe map[string]interface{}
+		if err := json.Unmarshal(bytes.TrimSuffix(bytes.TrimSpace(firstLine), []byte(",")), &probe); err == nil {
+			_, hasURL := probe["url"]
+			_, hasStatus := probe["status_code"]
+			_, hasLegacyStatus := probe["status-code"]
+			_, hasPorts := probe["ports"]
+			switch {
+			case hasURL && (hasStatus || hasLegacyStatus || probe["scheme"] != nil || probe["input"] != nil):
+				return FormatHttpx
+			case hasPorts:
+				return FormatMasscanJSON
+			case probe["port"] != nil:
+				return FormatNaabu
+			}
+		}
+	}
+
+	return FormatList
+}
+
+// isMasscanJSON reports whether content starting with '[' is a masscan JSON
+// array: either valid JSON as a whole or a first host object per line
+func isMasscanJSON(data []byte) bool {
+	var entries []map[string]interface{}
+	if err := json.Unmarshal(data, &entries); err == nil {
+		return true
+	}
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		line = bytes.TrimSpace(bytes.TrimPrefix(line, []byte("[")))
+		line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("]")), []byte(","))
+		if len(line) == 0 {
+			continue
+		}
+		var probe map[string]interface{}
+		if err := json.Unmarshal(line, &probe); err != nil {
+			return false
+		}
+		return probe["ip"] != nil || probe["ports"] != nil
+	}
+	return false
+}
+
+// detectSize is how much of a file is inspected to detect its format
+const detectSize = 64 * 1024
+
+// probeBatch is how many candidates are collected before they are probed
+const probeBatch = 4096
+
+// errStopped ends a parser when the consumer of the targets stops early
+var errStopped = errors.New("stopped")
+
+// ParseFile detects the format of a recon output and converts it into scan
+// targets. Files that are not recognised are read as plain lists, where CIDR
+// and IP range entries are expanded into candidate URLs.
+func ParseFile(fileName string, opts Options) (*Result, error) {
+	result := &Result{}
+	for target, err := range Targets(fileName, opts, result) {
+		if err != nil {
+			return nil, err
+		}
+		result.Targets = append(result.Targets, target)
+	}
+	return result, nil
+}
+
+// Targets streams the targets of a recon output or plain list while the file
+// is read, so scans can start before a large file is parsed. The format and
+// counters of result are filled in as targets are yielded; result may be nil.
+func Targets(fileName string, opts Options, result *Result) iter.Seq2[Target, error] {
+	return func(yield func(Target, error) bool) {
+		if result == nil {
+			result = &Result{}
+		}
+
+		file, err := os.Open(fileName)
+		if err != nil {
+			yield(Target{}, fmt.Errorf("failed to read %v: %v", fileName, err))
+			return
+		}
+		defer file.Close()
+
+		reader := bufio.NewReaderSize(file, detectSize)
+		// Peek returns what is available for files shorter than detectSize
+		head, _ := reader.Peek(detectSize)
+		result.Format = DetectFormat(head)
+
+		emit := filter(opts, result, yield)
+		switch result.Format {
+		case FormatHttpx:
+			err = scanHttpx(reader, result, emit)
+		case FormatNaabu:
+			err = scanNaabu(reader, result, emit)
+		case FormatMasscanXML:
+			err = streamMasscanXML(reader, result, emit)
+		case FormatMasscanJSON:
+			err = scanMasscanJSON(reader, result, emit)
+		case FormatNmapXML:
+			for scanURL, nmapErr := range nmap.Targets(fileName) {
+				if nmapErr != nil {
+					err = nmapErr
+					break
+				}
+				if !emit(Target{URL: scanURL}) {
+					err = errStopped
+					break
+				}
+			}
+		default:
+			err = scanList(reader, opts, result, emit)
+		}
+		if err != nil && err != errStopped {
+			yield(Target{}, err)
+		}
+	}
+}
+
+// Ranges streams the targets of CIDRs, IP ranges, single IPs and URLs given on
+// the command line, with the same expansion and probing as plain list files
+func Ranges(entries []string, opts Options, result *Result) iter.Seq2[Target, error] {
+	return func(yield func(Target, error) bool) {
+		if result == nil {
+			result = &Result{}
+		}
+
+		list := &listExpander{opts: opts, result: result, emit: filter(opts, result, yield)}
+		for _, entry := range entries {
+			if err := list.add(entry); err != nil {
+				if err != errStopped {
+					yield(Target{}, err)
+				}
+				return
+			}
+		}
+		if err := list.flush(); err != nil && err != errStopped {
+			yield(Target{}, err)
+		}
+	}
+}
+
+// URLs drops targets whose URL was already yielded and returns the URLs
+func URLs(targets iter.Seq2[Target, error]) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		seen := make(map[string]bool)
+		for target, err := range targets {
+			if err != nil {
+				yield("", err)
+				return
+			}
+			if seen[target.URL] {
+				continue
+			}
+			seen[target.URL] = true
+			if !yield(target.URL, nil) {
+				return
+			}
+		}
+	}
+}
+
+// CheckCandidates counts the candidate URLs that the range entries of a plain
+// list file and of entries expand to, and fails above limit. It runs before the
+// first scan is submitted; recon tool outputs carry no range entries and are
+// not read.
+func CheckCandidates(fileName string, entries []string, opts Options, limit int) (int, error) {
+	opts.Probe = nil
+	seen := make(map[string]bool)
+	count := func(targets iter.Seq2[Target, error]) error {
+		for target, err := range targets {
+			if err != nil {
+				return err
+			}
+			if !target.Candidate || seen[target.URL] {
+				continue
+			}
+			seen[target.URL] = true
+			if len(seen) > limit {
+				return fmt.Errorf("ranges expand to more than %d candidate URLs", limit)
+			}
+		}
+		return nil
+	}
+
+	if fileName != "" {
+		format, err := fileFormat(fileName)
+		if err != nil {
+			return 0, err
+		}
+		if format == FormatList {
+			if err := count(Targets(fileName, opts, nil)); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := count(Ranges(entries, opts, nil)); err != nil {
+		return 0, err
+	}
+	return len(seen), nil
+}
+
+// fileFormat detects the format of a file from its first bytes
+func fileFormat(fileName string) (string, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %v: %v", fileName, err)
+	}
+	defer file.Close()
+
+	head := make([]byte, detectSize)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("failed to read %v: %v", fileName, err)
+	}
+	return DetectFormat(head[:n]), nil
+}
+
+// filter returns an emit function that applies the status and title filters
+// before yielding. It returns false once the consumer stops.
+func filter(opts Options, result *Result, yield func(Target, error) bool) func(Target) bool {
+	return func(target Target) bool {
+		if !opts.matches(target) {
+			result.Filtered++
+			return true
+		}
+		return yield(target, nil)
+	}
+}
+
+// matches applies status and title filters to targets with HTTP information
+func (f Options) matches(target Target) bool {
+	if len(f.StatusCodes) > 0 && target.StatusCode != 0 {
+		found := false
+		for _, code := range f.StatusCodes {
+			if code == target.StatusCode {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Title != nil && target.StatusCode != 0 && !f.Title.MatchString(target.Title) {
+		return false
+	}
+	return true
+}
+
+// scanList keeps URLs of a plain list as they are and expands range entries
+func scanList(r io.Reader, opts Options, result *Result, emit func(Target) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	list := &listExpander{opts: opts, result: result, emit: emit}
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := list.add(line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read list: %v", err)
+	}
+	return list.flush()
+}
+
+// listExpander emits list entries, expanding range entries into candidates.
+// With a probe, candidates are collected and probed in batches.
+type listExpander struct {
+	opts    Options
+	result  *Result
+	emit    func(Target) bool
+	pending []Target
+}
+
+// add emits a URL or the candidates of a range entry
+func (l *listExpander) add(entry string) error {
+	if !IsRangeEntry(entry) {
+		if !l.emit(Target{URL: entry}) {
+			return errStopped
+		}
+		return nil
+	}
+
+	candidates, err := ExpandEntry(entry, l.opts.Ports)
+	if err != nil {
+		return fmt.Errorf("failed to expand %v: %v", entry, err)
+	}
+	l.result.Expanded++
+
+	if l.opts.Probe == nil {
+		for _, candidate := range candidates {
+			if !l.emit(candidate) {
+				return errStopped
+			}
+		}
+		return nil
+	}
+
+	l.pending = append(l.pending, candidates...)
+	if len(l.pending) >= probeBatch {
+		return l.flush()
+	}
+	return nil
+}
+
+// flush probes the pending candidates and emits the live ones
+func (l *listExpander) flush() error {
+	if len(l.pending) == 0 {
+		return nil
+	}
+	live := Probe(l.pending, *l.opts.Probe)
+	l.pending = nil
+	l.result.Live += len(live)
+	for _, target := range live {
+		if !l.emit(target) {
+			return errStopped
+		}
+	}
+	return nil
+}
+
+// buildURL formats a URL and omits default ports
+func buildURL(scheme, host string, port int) string {
+	if strings.Contains(host, ":") && !strings.HasPrefix(host, "[") {
+		host = "[" + host + "]"
+	}
+	if port == 0 || (scheme == "http" && port == 80) || (scheme == "https" && port == 443) {
+		return scheme + "://" + host
+	}
+	return scheme + "://" + host + ":" + strconv.Itoa(port)
+}
+
+// forEachJSONLine decodes JSON lines, tolerating trailing commas and array brackets
+func forEachJSONLine(r io.Reader, fn func(line []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		line = bytes.TrimSuffix(line, []byte(","))
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// scanHttpx reads httpx -json output
+func scanHttpx(r io.Reader, result *Result, emit func(Target) bool) error {
+	return forEachJSONLine(r, func(line []byte) error {
+		var entry struct {
+			URL              string      `json:"url"`
+			Host             string      `json:"host"`
+			Port             json.Number `json:"port"`
+			Scheme           string      `json:"scheme"`
+			StatusCode       int         `json:"status_code"`
+			LegacyStatusCode int         `json:"status-code"`
+			Title            string      `json:"title"`
+			Failed           bool        `json:"failed"`
+		}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			result.Malformed++
+			return nil
+		}
+		if entry.Failed || entry.URL == "" {
+			return nil
+		}
+
+		port, _ := strconv.Atoi(entry.Port.String())
+		status := entry.StatusCode
+		if status == 0 {
+			status = entry.LegacyStatusCode
+		}
+
+		if !emit(Target{
+			URL:        entry.URL,
+			Host:       entry.Host,
+			Port:       port,
+			Scheme:     entry.Scheme,
+			StatusCode: status,
+			Title:      entry.Title,
+		}) {
+			return errStopped
+		}
+		return nil
+	})
+}
+
+// scanNaabu reads naabu -json output
+func scanNaabu(r io.Reader, result *Result, emit func(Target) bool) error {
+	return forEachJSONLine(r, func(line []byte) error {
+		var entry struct {
+			Host     string `json:"host"`
+			IP       string `json:"ip"`
+			Port     int    `json:"port"`
+			Protocol string `json:"protocol"`
+			TLS      bool   `json:"tls"`
+		}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			result.Malformed++
+			return nil
+		}
+
+		host := entry.Host
+		if host == "" {
+			host = entry.IP
+		}
+		return emitPort(host, entry.Port, entry.TLS, result, emit)
+	})
+}
+
+// streamMasscanXML decodes the <host> elements of masscan -oX output one at a
+// time
+func streamMasscanXML(r io.Reader, result *Result, emit func(Target) bool) error {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse masscan XML: %v", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "host" {
+			continue
+		}
+
+		var host struct {
+			Addresses []struct {
+				Addr string `xml:"addr,attr"`
+			} `xml:"address"`
+			Ports []struct {
+				PortID int `xml:"portid,attr"`
+				State  struct {
+					State string `xml:"state,attr"`
+				} `xml:"state"`
+			} `xml:"ports>port"`
+		}
+		if err := decoder.DecodeElement(&host, &start); err != nil {
+			return fmt.Errorf("failed to parse masscan XML: %v", err)
+		}
+		if len(host.Addresses) == 0 {
+			continue
+		}
+		for _, port := range host.Ports {
+			if port.State.State != "" && port.State.State != "open" {
+				continue
+			}
+			if err := emitPort(host.Addresses[0].Addr, port.PortID, false, result, emit); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// scanMasscanJSON reads masscan -oJ output, which is one object per line
+func scanMasscanJSON(r io.Reader, result *Result, emit func(Target) bool) error {
+	return forEachJSONLine(r, func(line []byte) error {
+		var entry struct {
+			IP    string `json:"ip"`
+			Ports []struct {
+				Port   int    `json:"port"`
+				Status string `json:"status"`
+			} `json:"ports"`
+		}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			// The final "finished" record is not a host entry
+			return nil
+		}
+		for _, port := range entry.Ports {
+			if port.Status != "" && port.Status != "open" {
+				continue
+			}
+			if err := emitPort(entry.IP, port.Port, false, result, emit); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// emitPort emits an open port that is a known web port and counts the others
+// as skipped
+func emitPort(host string, port int, tls bool, result *Result, emit func(Target) bool) error {
+	target, ok := portTarget(host, port, tls)
+	if !ok {
+		result.Skipped++
+		return nil
+	}
+	if !emit(target) {
+		return errStopped
+	}
+	return nil
+}
+
+// portTarget turns an open port into a target if it is a known web port
+func portTarget(host string, port int, tls bool) (Target, bool) {
+	if host == "" {
+		return Target{}, false
+	}
+	scheme, ok := webPorts[port]
+	if !ok && !tls {
+		return Target{}, false
+	}
+	if tls {
+		scheme = "https"
+	}
+	return Target{
+		URL:    buildURL(scheme, host, port),
+		Host:   host,
+		Port:   port,
+		Scheme: scheme,
+	}, true
+}
@@ -0,0 +1,126 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// Fixtures from real tool output; each one ends with a line cut off by an
+// interrupted run
+const (
+	httpxFixture = `{"timestamp":"2025-06-02T10:00:00.123456789+02:00","port":"443","url":"https://www.example.com","input":"www.example.com","title":"Example Domain","scheme":"https","webserver":"ECS (dcb/7F83)","content_type":"text/html","method":"GET","host":"93.184.215.14","path":"/","time":"120.5ms","a":["93.184.215.14"],"words":298,"lines":47,"status_code":200,"content_length":1256,"failed":false}
+{"timestamp":"2025-06-02T10:00:00.234567891+02:00","port":"8080","url":"http://admin.example.com:8080","input":"admin.example.com:8080","title":"Login","scheme":"http","webserver":"nginx","content_type":"text/html","method":"GET","host":"93.184.215.15","path":"/","time":"80.1ms","words":12,"lines":3,"status_code":302,"content_length":154,"failed":false}
+{"timestamp":"2025-06-02T10:00:01.000000000+02:00","port":"443","url":"https://down.example.com","input":"down.example.com","failed":true}
+{"timestamp":"2025-06-02T10:00:01.345678912+02:00","port":"443","url":"https://api.exa
+`
+	naabuFixture = `{"host":"www.example.com","ip":"93.184.215.14","port":443,"protocol":"tcp","tls":true,"timestamp":"2025-06-02T08:00:00.000Z"}
+{"host":"www.example.com","ip":"93.184.215.14","port":22,"protocol":"tcp","tls":false,"timestamp":"2025-06-02T08:00:00.100Z"}
+{"ip":"93.184.215.15","port":8080,"protocol":"tcp","tls":false,"timestamp":"2025-06-02T08:00:00.200Z"}
+{"host":"api.example.com","ip":"93.184.2
+`
+	masscanJSONFixture = `[
+{   "ip": "10.0.0.1",   "timestamp": "1717315200", "ports": [ {"port": 80, "proto": "tcp", "status": "open", "reason": "syn-ack", "ttl": 64} ] },
+{   "ip": "10.0.0.2",   "timestamp": "1717315200", "ports": [ {"port": 22, "proto": "tcp", "status": "open", "reason": "syn-ack", "ttl": 64} ] },
+{   "ip": "10.0.0.3",   "timestamp": "1717315201", "ports": [ {"port": 8443, "proto": "tcp", "status": "open", "reason": "syn-ack", "ttl": 128} ] },
+{   "ip": "10.0.0.4",   "timestamp": "1717315201", "ports": [ {"port": 80, "pro
+{finished: 1}
+]
+`
+	masscanXMLFixture = `<?xml version="1.0"?>
+<!-- masscan v1.3 scan -->
+<nmaprun scanner="masscan" start="1717315200" version="1.0-BETA"  xmloutputversion="1.03">
+<scaninfo type="syn" protocol="tcp" />
+<host endtime="1717315200"><address addr="10.0.0.1" addrtype="ipv4"/><ports><port protocol="tcp" portid="443"><state state="open" reason="syn-ack" reason_ttl="64"/></port></ports></host>
+<host endtime="1717315201"><address addr="10.0.0.2" addrtype="ipv4"/><ports><port protocol="tcp" portid="3306"><state state="open" reason="syn-ack" reason_ttl="64"/></port></ports></host>
+<host endtime="1717315201"><ports><port protocol="tcp" portid="80"><state state="open" reason="syn-ack" reason_ttl="64"/></port></ports></host>
+<runstats>
+<finished time="1717315210" timestr="2025-06-02 10:00:10" elapsed="10" />
+<hosts up="3" down="0" total="3" />
+</runstats>
+</nmaprun>
+`
+	listFixture = `# targets
+https://www.example.com
+[::1]:8080
+
+10.0.0.0/30 80,8080
+`
+)
+
+func TestParseFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		format    string
+		want      []string
+		skipped   int
+		malformed int
+	}{
+		{"httpx", httpxFixture, FormatHttpx, []string{"https://www.example.com", "http://admin.example.com:8080"}, 0, 1},
+		{"naabu", naabuFixture, FormatNaabu, []string{"https://www.example.com", "http://93.184.215.15:8080"}, 1, 1},
+		// masscan's closing record is not a host and not counted
+		{"masscan json", masscanJSONFixture, FormatMasscanJSON, []string{"http://10.0.0.1", "https://10.0.0.3:8443"}, 1, 0},
+		// 3306 is not a web port; the host without an address is ignored
+		{"masscan xml", masscanXMLFixture, FormatMasscanXML, []string{"https://10.0.0.1"}, 1, 0},
+		{"list", listFixture, FormatList, []string{
+			"https://www.example.com", "[::1]:8080",
+			"http://10.0.0.1", "http://10.0.0.1:8080", "http://10.0.0.2", "http://10.0.0.2:8080",
+		}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "targets")
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+
+		result, err := ParseFile(path, Options{})
+		if err != nil {
+			t.Errorf("%s: ParseFile failed: %v", tt.name, err)
+			continue
+		}
+		if result.Format != tt.format {
+			t.Errorf("%s: detected %s, want %s", tt.name, result.Format, tt.format)
+		}
+		if got := result.URLs(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: URLs = %v, want %v", tt.name, got, tt.want)
+		}
+		if result.Skipped != tt.skipped || result.Malformed != tt.malformed {
+			t.Errorf("%s: skipped %d and malformed %d, want %d and %d", tt.name, result.Skipped, result.Malformed, tt.skipped, tt.malformed)
+		}
+	}
+}
+
+func TestExpandEntry(t *testing.T) {
+	tests := []struct {
+		entry   string
+		want    []string
+		wantErr bool
+	}{
+		{"10.0.0.0/30", []string{"http://10.0.0.1", "https://10.0.0.1", "http://10.0.0.2", "https://10.0.0.2"}, false},
+		{"10.0.0.5-6 8443", []string{"https://10.0.0.5:8443", "https://10.0.0.6:8443"}, false},
+		{"10.0.0.1-10.0.0.2:9999", []string{"http://10.0.0.1:9999", "https://10.0.0.1:9999", "http://10.0.0.2:9999", "https://10.0.0.2:9999"}, false},
+		{"10.0.0.9 80,443", []string{"http://10.0.0.9", "https://10.0.0.9"}, false},
+		{"10.0.0.0/8", nil, true},
+		{"10.0.0.9-3", nil, true},
+		{"10.0.0.1-10.0.0.2 80,99999", nil, true},
+		{"10.0.0.1-300", nil, true},
+	}
+
+	for _, tt := range tests {
+		targets, err := ExpandEntry(tt.entry, nil)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ExpandEntry(%q) error = %v, want error %v", tt.entry, err, tt.wantErr)
+			continue
+		}
+		var got []string
+		for _, target := range targets {
+			got = append(got, target.URL)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ExpandEntry(%q) = %v, want %v", tt.entry, got, tt.want)
+		}
+	}
+}
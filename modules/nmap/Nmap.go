@@ -2,13 +2,11 @@ package nmap
 
 import (
 	"bufio"
-	"errors"
-	"io/ioutil"
-	"log"
-	"net/http"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"iter"
 	"os"
-	"strconv"
-	"strings"
 
 	"github.com/joanbono/color"
 	nmap "github.com/tomsteele/go-nmap"
@@ -16,58 +14,147 @@ import (
 
 var red = color.New(color.Bold, color.FgRed).SprintfFunc()
 
+// detectSize is how much of a file is inspected to detect its format
+const detectSize = 64 * 1024
+
 //ParseNmap returns a slice of targets
 func ParseNmap(fileName string) (result []string, err error) {
+	for target, err := range Targets(fileName) {
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, target)
+	}
+	return result, nil
+}
 
-	dat, err := ioutil.ReadFile(fileName)
-	if err != nil {
-		log.Fatal(err)
+// Targets streams web targets from an Nmap XML, grepable or JSON file. XML and
+// grepable files are parsed host by host, so scans can be started while a large
+// file is still being read. A parse error is yielded last with an empty target.
+func Targets(fileName string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		stopped := false
+		err := walkHosts(fileName, func(host hostRecord) bool {
+			for _, target := range host.targets() {
+				if !yield(target, nil) {
+					stopped = true
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil && !stopped {
+			yield("", err)
+		}
 	}
+}
 
-	contentType := http.DetectContentType(dat)
-	isXML := strings.Split(contentType, ";")
+// walkHosts detects the file format and calls fn for every host until fn
+// returns false
+func walkHosts(fileName string, fn func(hostRecord) bool) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to open %v: %v", fileName, err)
+	}
+	defer file.Close()
 
-	if isXML[0] == "text/xml" {
-		scan, err := nmap.Parse(dat)
+	reader := bufio.NewReaderSize(file, detectSize)
+	// Peek returns what is available for files shorter than detectSize
+	head, _ := reader.Peek(detectSize)
+
+	switch DetectFormat(head) {
+	case FormatXML:
+		return streamXML(reader, fn)
+	case FormatGrepable:
+		return scanGrepable(reader, fn)
+	case FormatJSON:
+		// JSON renderings nest hosts at different depths, so they are decoded whole
+		data, err := io.ReadAll(reader)
 		if err != nil {
-			log.Fatal(err)
+			return fmt.Errorf("failed to read %v: %v", fileName, err)
 		}
-		for i, host := range scan.Hosts {
-			for _, port := range host.Ports {
-				if port.Service.Name == "http" || port.Service.Name == "https" {
-					target := port.Service.Name + "://" + host.Addresses[i].Addr + ":" + strconv.Itoa(port.PortId)
-					result = append(result, target)
-					// fmt.Printf("Ip: %s Port: %v \n", host.Addresses[i].Addr, port.PortId)
-				}
-
+		hosts, err := parseJSON(data)
+		if err != nil {
+			return err
+		}
+		for _, host := range hosts {
+			if !fn(host) {
+				return nil
 			}
-
 		}
+		return nil
+	case FormatNormal:
+		return fmt.Errorf("unsupported nmap output format in %v: use XML (-oX), grepable (-oG) or JSON output", fileName)
+	default:
+		return fmt.Errorf("%v is not an Nmap XML, grepable or JSON file", fileName)
+	}
+}
 
-	} else {
-		err = errors.New("This file is not an xml file")
+// streamXML decodes <host> elements one at a time instead of loading the whole
+// document
+func streamXML(r io.Reader, fn func(hostRecord) bool) error {
+	decoder := xml.NewDecoder(r)
+	// Nmap output is ASCII/UTF-8 even when another charset is declared
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
 	}
-	return result, err
 
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse Nmap XML: %v", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "host" {
+			continue
+		}
+
+		var host nmap.Host
+		if err := decoder.DecodeElement(&host, &start); err != nil {
+			return fmt.Errorf("failed to parse Nmap XML host: %v", err)
+		}
+		if !fn(fromXMLHost(host)) {
+			return nil
+		}
+	}
 }
 
-// ParseFile return a slice of targets
-func ParseFile(filename string) (result []string) {
+// Lines streams the lines of a plain target list
+func Lines(filename string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		file, err := os.Open(filename)
+		if err != nil {
+			yield("", fmt.Errorf("failed to open %v: %v", filename, err))
+			return
+		}
+		defer file.Close()
 
-	file, err := os.Open(filename)
-	if err != nil {
-		log.Fatal(err)
+		scanner := bufio.NewScanner(file)
+		Buf := make([]byte, 0, 2048*2048) //bytes.Buffer
+		scanner.Buffer(Buf, 10*2048*2048)
+
+		for scanner.Scan() {
+			if !yield(scanner.Text(), nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield("", fmt.Errorf("failed to read %v: %v", filename, err))
+		}
 	}
-	defer file.Close()
-	scanner := bufio.NewScanner(file)
-	Buf := make([]byte, 0, 2048*2048) //bytes.Buffer
-	scanner.Buffer(Buf, 10*2048*2048)
+}
 
-	for scanner.Scan() {
-		target := scanner.Text()
+// ParseFile return a slice of targets
+func ParseFile(filename string) (result []string, err error) {
+	for target, err := range Lines(filename) {
+		if err != nil {
+			return nil, err
+		}
 		result = append(result, target)
 	}
-
-	return result
-
+	return result, nil
 }
@@ -0,0 +1,268 @@
+package nmap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Supported Nmap output formats
+const (
+	FormatUnknown  = "unknown"
+	FormatXML      = "xml"
+	FormatGrepable = "grepable"
+	FormatJSON     = "json"
+	// FormatNormal is -oN output, which is meant for humans and not parsed
+	FormatNormal = "normal"
+)
+
+// DetectFormat inspects the start of a file and returns the Nmap output format
+func DetectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return FormatUnknown
+	}
+
+	switch trimmed[0] {
+	case '<':
+		if bytes.Contains(trimmed[:min(len(trimmed), 4096)], []byte("<nmaprun")) {
+			return FormatXML
+		}
+	case '{', '[':
+		return FormatJSON
+	}
+
+	// -oN and -oG output share the "# Nmap" header, only -oG has tab separated
+	// "Host: " lines. The header's command line tells them apart when no host
+	// was up.
+	header := ""
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	for lines := 0; scanner.Scan() && lines < 20; lines++ {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "Host: ") && strings.Contains(line, "\t") {
+			return FormatGrepable
+		}
+		if header == "" && strings.HasPrefix(line, "# Nmap") {
+			header = line
+		}
+	}
+	if header == "" {
+		return FormatUnknown
+	}
+	if strings.Contains(header, " -oG ") || strings.Contains(header, " -oA ") {
+		return FormatGrepable
+	}
+	return FormatNormal
+}
+
+// scanGrepable streams -oG output. Status and ports are written on separate
+// lines, so the status seen for an address is remembered until its ports line.
+// fn is called once per ports line and stops the scan by returning false.
+func scanGrepable(r io.Reader, fn func(hostRecord) bool) error {
+	status := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "Host: ") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		// "Host: 10.0.0.1 (www.example.com)"
+		hostField := strings.Fields(strings.TrimPrefix(fields[0], "Host: "))
+		if len(hostField) == 0 {
+			continue
+		}
+		address := hostField[0]
+
+		host := hostRecord{Addresses: []string{address}, Status: status[address]}
+		if len(hostField) > 1 {
+			if name := strings.Trim(hostField[1], "()"); name != "" {
+				host.Hostnames = []string{name}
+			}
+		}
+
+		for _, field := range fields[1:] {
+			switch {
+			case strings.HasPrefix(field, "Status: "):
+				status[address] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(field, "Status: ")))
+				host.Status = status[address]
+			case strings.HasPrefix(field, "Ports: "):
+				host.Ports = append(host.Ports, parseGrepablePorts(strings.TrimPrefix(field, "Ports: "))...)
+			}
+		}
+
+		if len(host.Ports) > 0 && !fn(host) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// parseGrepablePorts reads "80/open/tcp//http///, 443/open/tcp//ssl|http///"
+func parseGrepablePorts(value string) []portRecord {
+	var ports []portRecord
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), "/")
+		if len(parts) < 5 {
+			continue
+		}
+		portID, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+
+		port := portRecord{Port: portID, State: parts[1], Service: parts[4]}
+		// Grepable output writes SSL tunnels as "ssl|http"
+		if strings.HasPrefix(port.Service, "ssl|") {
+			port.Tunnel = "ssl"
+			port.Service = strings.TrimPrefix(port.Service, "ssl|")
+		}
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+// parseJSON reads JSON renderings of Nmap results: go-nmap's encoding, xml2json
+// style {"nmaprun": {"host": [...]}} documents with or without "@" attribute
+// prefixes, and plain arrays of hosts.
+func parseJSON(data []byte) ([]hostRecord, error) {
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse Nmap JSON: %v", err)
+	}
+
+	var hostList []interface{}
+	switch value := root.(type) {
+	case []interface{}:
+		hostList = value
+	case map[string]interface{}:
+		if run := field(value, "nmaprun"); run != nil {
+			value, _ = run.(map[string]interface{})
+		}
+		hostList = asList(field(value, "host", "hosts"))
+	}
+	if len(hostList) == 0 {
+		return nil, fmt.Errorf("no hosts found in Nmap JSON")
+	}
+
+	var records []hostRecord
+	for _, raw := range hostList {
+		host, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		record := hostRecord{Status: strings.ToLower(text(field(host, "status"), "state"))}
+
+		for _, raw := range asList(field(host, "address", "addresses")) {
+			address, _ := raw.(map[string]interface{})
+			addrType := strings.ToLower(text(field(address, "addrtype", "type")))
+			if addrType == "" || addrType == "ipv4" || addrType == "ipv6" {
+				if addr := text(field(address, "addr", "address")); addr != "" {
+					record.Addresses = append(record.Addresses, addr)
+				}
+			}
+		}
+		// Some converters flatten the address into the host object
+		if len(record.Addresses) == 0 {
+			if addr := text(field(host, "ip", "addr")); addr != "" {
+				record.Addresses = append(record.Addresses, addr)
+			}
+		}
+
+		hostnames := field(host, "hostnames")
+		if nested, ok := hostnames.(map[string]interface{}); ok {
+			hostnames = field(nested, "hostname")
+		}
+		for _, raw := range asList(hostnames) {
+			if name := text(raw, "name"); name != "" {
+				record.Hostnames = append(record.Hostnames, name)
+			}
+		}
+
+		ports := field(host, "ports")
+		if nested, ok := ports.(map[string]interface{}); ok {
+			ports = field(nested, "port")
+		}
+		for _, raw := range asList(ports) {
+			port, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			portID, _ := strconv.Atoi(text(field(port, "portid", "id", "port")))
+			if portID == 0 {
+				continue
+			}
+			service := field(port, "service")
+			record.Ports = append(record.Ports, portRecord{
+				Port:    portID,
+				State:   strings.ToLower(text(field(port, "state"), "state")),
+				Service: text(service, "name"),
+				Tunnel:  text(field(asMap(service), "tunnel")),
+			})
+		}
+
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// field returns the first present key, accepting xml2json "@" and "-" prefixes
+func field(object map[string]interface{}, keys ...string) interface{} {
+	if object == nil {
+		return nil
+	}
+	for _, key := range keys {
+		for _, candidate := range []string{key, "@" + key, "-" + key, "_" + key} {
+			if value, ok := object[candidate]; ok {
+				return value
+			}
+		}
+	}
+	return nil
+}
+
+// text renders a scalar, or the given key of an object, as a string
+func text(value interface{}, keys ...string) string {
+	if object, ok := value.(map[string]interface{}); ok {
+		if len(keys) == 0 {
+			return ""
+		}
+		value = field(object, keys...)
+	}
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// asList treats single objects as one-element lists, as xml2json emits them
+func asList(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	default:
+		return []interface{}{v}
+	}
+}
+
+// asMap returns value as an object, or nil
+func asMap(value interface{}) map[string]interface{} {
+	object, _ := value.(map[string]interface{})
+	return object
+}
@@ -0,0 +1,110 @@
+package nmap
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// Grepable output of "nmap -sV -oG", with a host line that lost its address
+// and one with broken port entries
+const grepableFixture = `# Nmap 7.94SVN scan initiated Mon Jun  2 10:00:00 2025 as: nmap -sV -oG scan.gnmap 10.0.0.0/29
+Host: 10.0.0.1 (www.example.com)	Status: Up
+Host: 10.0.0.1 (www.example.com)	Ports: 22/open/tcp//ssh//OpenSSH 9.6p1 Ubuntu 3ubuntu13 (Ubuntu Linux; protocol 2.0)/, 80/open/tcp//http//nginx 1.24.0 (Ubuntu)/, 443/open/tcp//ssl|http//nginx 1.24.0 (Ubuntu)/, 8080/closed/tcp//http-proxy///	Ignored State: filtered (996)
+Host: 10.0.0.2 ()	Status: Up
+Host: 10.0.0.2 ()	Ports: 8443/open/tcp//https-alt///
+Host: 	Ports: 80/open/tcp//http///
+Host: 10.0.0.3 ()	Ports: http/open/tcp//http///, 81/open
+Host: 10.0.0.4 ()	Status: Down
+Host: 10.0.0.4 ()	Ports: 80/open/tcp//http///
+# Nmap done at Mon Jun  2 10:00:05 2025 -- 8 IP addresses (3 hosts up) scanned in 5.01 seconds
+`
+
+// xml2json rendering of "nmap -sV -oX", with a host that is not an object
+// and a port without a number
+const jsonFixture = `{
+  "nmaprun": {
+    "@scanner": "nmap",
+    "@args": "nmap -sV -oX scan.xml 10.0.0.1",
+    "host": [
+      {
+        "status": {"@state": "up", "@reason": "syn-ack"},
+        "address": [
+          {"@addr": "10.0.0.1", "@addrtype": "ipv4"},
+          {"@addr": "52:54:00:12:34:56", "@addrtype": "mac"}
+        ],
+        "hostnames": {"hostname": {"@name": "www.example.com", "@type": "PTR"}},
+        "ports": {
+          "port": [
+            {"@protocol": "tcp", "@portid": "80", "state": {"@state": "open"}, "service": {"@name": "http", "@product": "nginx"}},
+            {"@protocol": "tcp", "@portid": "443", "state": {"@state": "open"}, "service": {"@name": "http", "@tunnel": "ssl"}},
+            {"@protocol": "tcp", "@portid": "22", "state": {"@state": "open"}, "service": {"@name": "ssh"}},
+            {"@protocol": "tcp", "state": {"@state": "open"}, "service": {"@name": "http"}}
+          ]
+        }
+      },
+      "10.0.0.9",
+      {
+        "status": {"@state": "up"},
+        "address": {"@addr": "10.0.0.2", "@addrtype": "ipv4"},
+        "ports": {"port": {"@portid": "8080", "state": {"@state": "open"}, "service": {"@name": "http-proxy"}}}
+      }
+    ]
+  }
+}
+`
+
+func TestParseNmap(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "grepable",
+			content: grepableFixture,
+			want: []string{
+				"http://10.0.0.1:80", "http://www.example.com:80",
+				"https://10.0.0.1:443", "https://www.example.com:443",
+				"https://10.0.0.2:8443",
+			},
+		},
+		{
+			name:    "json",
+			content: jsonFixture,
+			want: []string{
+				"http://10.0.0.1:80", "http://www.example.com:80",
+				"https://10.0.0.1:443", "https://www.example.com:443",
+				"http://10.0.0.2:8080",
+			},
+		},
+		{
+			name:    "truncated json",
+			content: jsonFixture[:len(jsonFixture)/2],
+			wantErr: true,
+		},
+		{
+			name:    "normal output",
+			content: "# Nmap 7.94SVN scan initiated Mon Jun  2 10:00:00 2025 as: nmap -sV -oN scan.nmap 10.0.0.1\nNmap scan report for 10.0.0.1\nPORT   STATE SERVICE\n80/tcp open  http\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "scan")
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+
+		got, err := ParseNmap(path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ParseNmap error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !slices.Equal(got, tt.want) {
+			t.Errorf("%s: ParseNmap = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
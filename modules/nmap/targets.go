@@ -0,0 +1,112 @@
+package nmap
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	nmap "github.com/tomsteele/go-nmap"
+)
+
+// webServices maps Nmap service names to the scheme they are reached with
+var webServices = map[string]string{
+	"http":       "http",
+	"http-alt":   "http",
+	"http-proxy": "http",
+	"http-mgmt":  "http",
+	"https":      "https",
+	"https-alt":  "https",
+}
+
+// hostRecord is a format-neutral view of a scanned host
+type hostRecord struct {
+	Status    string
+	Addresses []string
+	Hostnames []string
+	Ports     []portRecord
+}
+
+// portRecord is a format-neutral view of a scanned port
+type portRecord struct {
+	Port    int
+	State   string
+	Service string
+	Tunnel  string
+}
+
+// WebScheme returns the scheme for an Nmap service name and tunnel, handling
+// tunnel="ssl" and "ssl/http" style names as https
+func WebScheme(service, tunnel string) (string, bool) {
+	service = strings.ToLower(strings.TrimSpace(service))
+	ssl := strings.EqualFold(tunnel, "ssl")
+	if strings.HasPrefix(service, "ssl/") {
+		ssl = true
+		service = strings.TrimPrefix(service, "ssl/")
+	}
+
+	scheme, ok := webServices[service]
+	if !ok {
+		return "", false
+	}
+	if ssl {
+		scheme = "https"
+	}
+	return scheme, true
+}
+
+// fromXMLHost converts a go-nmap host into a host record
+func fromXMLHost(host nmap.Host) hostRecord {
+	record := hostRecord{Status: host.Status.State}
+	for _, address := range host.Addresses {
+		// MAC addresses are reported next to the IP and are not reachable
+		if address.AddrType == "ipv4" || address.AddrType == "ipv6" || (address.AddrType == "" && net.ParseIP(address.Addr) != nil) {
+			record.Addresses = append(record.Addresses, address.Addr)
+		}
+	}
+	for _, hostname := range host.Hostnames {
+		record.Hostnames = append(record.Hostnames, hostname.Name)
+	}
+	for _, port := range host.Ports {
+		record.Ports = append(record.Ports, portRecord{
+			Port:    port.PortId,
+			State:   port.State.State,
+			Service: port.Service.Name,
+			Tunnel:  port.Service.Tunnel,
+		})
+	}
+	return record
+}
+
+// targets returns a URL for every open web port on every address and hostname
+// of the host. Hostnames are kept so virtual hosts are scanned by name.
+func (h hostRecord) targets() []string {
+	if h.Status != "" && h.Status != "up" {
+		return nil
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range append(append([]string{}, h.Addresses...), h.Hostnames...) {
+		name = strings.TrimSuffix(strings.TrimSpace(name), ".")
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		names = append(names, name)
+	}
+
+	var result []string
+	for _, port := range h.Ports {
+		if port.State != "" && port.State != "open" {
+			continue
+		}
+		scheme, ok := WebScheme(port.Service, port.Tunnel)
+		if !ok {
+			continue
+		}
+		for _, name := range names {
+			result = append(result, scheme+"://"+net.JoinHostPort(name, strconv.Itoa(port.Port)))
+		}
+	}
+	return result
+}
@@ -0,0 +1,427 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// httpMethods are the operation keys of an OpenAPI path item
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Endpoint is a single expanded operation of the specification
+type Endpoint struct {
+	Method string
+	URL    string
+}
+
+// SeedSet holds the seed URLs and scope prefixes derived from a specification
+type SeedSet struct {
+	Title     string
+	Version   string
+	Servers   []string
+	Endpoints []Endpoint
+}
+
+// URLs returns the unique seed URLs in specification order
+func (ss *SeedSet) URLs() []string {
+	seen := make(map[string]bool)
+	var urls []string
+	for _, endpoint := range ss.Endpoints {
+		if !seen[endpoint.URL] {
+			seen[endpoint.URL] = true
+			urls = append(urls, endpoint.URL)
+		}
+	}
+	return urls
+}
+
+// ScopeRules returns URL prefixes that match every seed URL
+func (ss *SeedSet) ScopeRules() []string {
+	var rules []string
+	for _, server := range ss.Servers {
+		rules = append(rules, strings.TrimSuffix(server, "/")+"/")
+	}
+	return rules
+}
+
+// spec wraps the decoded document so that local $refs can be resolved
+type spec struct {
+	root map[string]interface{}
+}
+
+// ParseSpec reads an OpenAPI 2 (Swagger) or OpenAPI 3 document in JSON or YAML
+// and expands every path and method into concrete seed URLs. serverOverride
+// replaces the absolute servers declared in the document; relative servers
+// such as "/api/v1" are resolved against it and are skipped without it.
+func ParseSpec(fileName, serverOverride string) (*SeedSet, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read specification %v: %v", fileName, err)
+	}
+
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			return nil, fmt.Errorf("specification is neither valid JSON nor YAML: %v", err)
+		}
+	}
+
+	s := &spec{root: root}
+	ss := &SeedSet{
+		Title:   asString(s.get(root, "info", "title")),
+		Version: asString(s.get(root, "info", "version")),
+	}
+
+	switch {
+	case asString(root["swagger"]) != "":
+		ss.Servers = s.swaggerServers()
+	case asString(root["openapi"]) != "":
+		ss.Servers = s.openAPIServers()
+	default:
+		return nil, fmt.Errorf("document has no 'openapi' or 'swagger' version field")
+	}
+
+	if serverOverride != "" {
+		servers, err := overrideServers(ss.Servers, serverOverride)
+		if err != nil {
+			return nil, err
+		}
+		ss.Servers = servers
+	}
+
+	var bases []string
+	for _, server := range ss.Servers {
+		u, err := url.Parse(server)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			continue
+		}
+		bases = append(bases, strings.TrimSuffix(server, "/"))
+	}
+	if len(bases) == 0 {
+		return nil, fmt.Errorf("no absolute server URL found in specification, use --openapi-server to provide one")
+	}
+	ss.Servers = bases
+
+	paths, _ := root["paths"].(map[string]interface{})
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("specification has no paths")
+	}
+
+	// Sort paths so the seed list is stable between runs
+	pathNames := make([]string, 0, len(paths))
+	for path := range paths {
+		pathNames = append(pathNames, path)
+	}
+	sort.Strings(pathNames)
+
+	for _, path := range pathNames {
+		item, _ := s.resolve(paths[path]).(map[string]interface{})
+		if item == nil {
+			continue
+		}
+		shared := s.parameters(item["parameters"])
+
+		for _, method := range httpMethods {
+			operation, ok := s.resolve(item[method]).(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			params := mergeParameters(shared, s.parameters(operation["parameters"]))
+			concretePath, query := s.fillParameters(path, params)
+
+			for _, base := range bases {
+				target := base + concretePath
+				if len(query) > 0 {
+					target += "?" + query.Encode()
+				}
+				ss.Endpoints = append(ss.Endpoints, Endpoint{
+					Method: strings.ToUpper(method),
+					URL:    target,
+				})
+			}
+		}
+	}
+
+	if len(ss.Endpoints) == 0 {
+		return nil, fmt.Errorf("no operations found in specification")
+	}
+
+	return ss, nil
+}
+
+// overrideServers applies --openapi-server to the declared servers: relative
+// servers keep their path below the override, absolute ones are replaced by it
+func overrideServers(declared []string, override string) ([]string, error) {
+	base, err := url.Parse(strings.TrimSuffix(override, "/") + "/")
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("--openapi-server must be an absolute URL: %v", override)
+	}
+
+	servers := []string{}
+	seen := make(map[string]bool)
+	add := func(server string) {
+		server = strings.TrimSuffix(server, "/")
+		if !seen[server] {
+			seen[server] = true
+			servers = append(servers, server)
+		}
+	}
+
+	for _, server := range declared {
+		ref, err := url.Parse(server)
+		if err != nil {
+			continue
+		}
+		if ref.Scheme != "" && ref.Host != "" {
+			add(override)
+			continue
+		}
+		add(base.ResolveReference(ref).String())
+	}
+	if len(servers) == 0 {
+		add(override)
+	}
+	return servers, nil
+}
+
+// swaggerServers builds base URLs from Swagger 2 schemes, host and basePath.
+// Without a host, the basePath is returned as a relative server.
+func (s *spec) swaggerServers() []string {
+	host := asString(s.root["host"])
+	basePath := asString(s.root["basePath"])
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []string{strings.TrimSuffix(basePath, "/")}
+	}
+
+	var schemes []string
+	if list, ok := s.root["schemes"].([]interface{}); ok {
+		for _, scheme := range list {
+			schemes = append(schemes, asString(scheme))
+		}
+	}
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+
+	var servers []string
+	for _, scheme := range schemes {
+		servers = append(servers, scheme+"://"+host+strings.TrimSuffix(basePath, "/"))
+	}
+	return servers
+}
+
+// openAPIServers builds base URLs from OpenAPI 3 servers and their variables
+func (s *spec) openAPIServers() []string {
+	list, _ := s.root["servers"].([]interface{})
+
+	var servers []string
+	for _, entry := range list {
+		server, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		serverURL := asString(server["url"])
+		if variables, ok := server["variables"].(map[string]interface{}); ok {
+			for name, raw := range variables {
+				variable, _ := raw.(map[string]interface{})
+				value := asString(variable["default"])
+				if value == "" {
+					if enum, ok := variable["enum"].([]interface{}); ok && len(enum) > 0 {
+						value = asString(enum[0])
+					}
+				}
+				serverURL = strings.Replace(serverURL, "{"+name+"}", value, -1)
+			}
+		}
+		servers = append(servers, strings.TrimSuffix(serverURL, "/"))
+	}
+	return servers
+}
+
+// parameters resolves a list of parameter objects
+func (s *spec) parameters(raw interface{}) []map[string]interface{} {
+	list, _ := raw.([]interface{})
+
+	var params []map[string]interface{}
+	for _, entry := range list {
+		if param, ok := s.resolve(entry).(map[string]interface{}); ok {
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// mergeParameters lets operation parameters override path-level parameters
+func mergeParameters(shared, own []map[string]interface{}) []map[string]interface{} {
+	key := func(param map[string]interface{}) string {
+		return asString(param["in"]) + ":" + asString(param["name"])
+	}
+
+	overridden := make(map[string]bool)
+	for _, param := range own {
+		overridden[key(param)] = true
+	}
+
+	merged := append([]map[string]interface{}{}, own...)
+	for _, param := range shared {
+		if !overridden[key(param)] {
+			merged = append(merged, param)
+		}
+	}
+	return merged
+}
+
+// fillParameters substitutes path parameters and collects query parameters
+func (s *spec) fillParameters(path string, params []map[string]interface{}) (string, url.Values) {
+	query := url.Values{}
+
+	for _, param := range params {
+		name := asString(param["name"])
+		if name == "" {
+			continue
+		}
+		value := s.exampleValue(param)
+
+		switch asString(param["in"]) {
+		case "path":
+			path = strings.Replace(path, "{"+name+"}", url.PathEscape(value), -1)
+		case "query":
+			query.Set(name, value)
+		}
+	}
+
+	// Any template left without a declared parameter still needs a value
+	for strings.Contains(path, "{") && strings.Contains(path, "}") {
+		start := strings.Index(path, "{")
+		end := strings.Index(path[start:], "}")
+		if end == -1 {
+			break
+		}
+		path = path[:start] + "1" + path[start+end+1:]
+	}
+
+	return path, query
+}
+
+// exampleValue picks a concrete value for a parameter from its example, default,
+// enum or type
+func (s *spec) exampleValue(param map[string]interface{}) string {
+	if value, ok := param["example"]; ok {
+		return asString(value)
+	}
+	if examples, ok := param["examples"].(map[string]interface{}); ok {
+		names := make([]string, 0, len(examples))
+		for name := range examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if example, ok := s.resolve(examples[name]).(map[string]interface{}); ok {
+				if value, ok := example["value"]; ok {
+					return asString(value)
+				}
+			}
+		}
+	}
+
+	// Swagger 2 keeps the type on the parameter, OpenAPI 3 in its schema
+	schema, _ := s.resolve(param["schema"]).(map[string]interface{})
+	if schema == nil {
+		schema = param
+	}
+	for _, candidate := range []map[string]interface{}{param, schema} {
+		if value, ok := candidate["default"]; ok {
+			return asString(value)
+		}
+		if value, ok := candidate["example"]; ok {
+			return asString(value)
+		}
+		if enum, ok := candidate["enum"].([]interface{}); ok && len(enum) > 0 {
+			return asString(enum[0])
+		}
+	}
+
+	switch asString(schema["type"]) {
+	case "integer", "number":
+		return "1"
+	case "boolean":
+		return "true"
+	case "array":
+		if items, ok := s.resolve(schema["items"]).(map[string]interface{}); ok {
+			return s.exampleValue(map[string]interface{}{"schema": items})
+		}
+		return "1"
+	}
+
+	switch asString(schema["format"]) {
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000001"
+	case "date":
+		return "2024-01-01"
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "email":
+		return "test@example.com"
+	}
+
+	return "test"
+}
+
+// resolve follows local "#/..." references
+func (s *spec) resolve(value interface{}) interface{} {
+	for depth := 0; depth < 10; depth++ {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		ref := asString(object["$ref"])
+		if !strings.HasPrefix(ref, "#/") {
+			return value
+		}
+		keys := strings.Split(strings.TrimPrefix(ref, "#/"), "/")
+		for i, key := range keys {
+			keys[i] = strings.Replace(strings.Replace(key, "~1", "/", -1), "~0", "~", -1)
+		}
+		value = s.get(s.root, keys...)
+	}
+	return value
+}
+
+// get walks nested objects by key
+func (s *spec) get(value interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+// asString renders scalar values from JSON or YAML documents
+func asString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		if v == float64(int64(v)) {
+			return fmt.Sprintf("%d", int64(v))
+		}
+		return fmt.Sprintf("%v", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
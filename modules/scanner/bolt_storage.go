@@ -0,0 +1,455 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Bucket names of the scan history database
+var (
+	scansBucket     = []byte("scans")
+	statusIndex     = []byte("index_status")
+	urlIndex        = []byte("index_url")
+	instanceIndex   = []byte("index_instance")
+	startedIndex    = []byte("index_started")
+	metaBucket      = []byte("meta")
+	samplesBucket   = []byte("samples")
+	migratedKey     = []byte("migrated_json")
+	schemaKey       = []byte("schema_version")
+	indexSeparator  = []byte{0}
+	boltLockTimeout = 30 * time.Second
+	schemaBuckets   = [][]byte{scansBucket, statusIndex, urlIndex, instanceIndex, startedIndex, metaBucket, samplesBucket}
+	indexBuckets    = [][]byte{statusIndex, urlIndex, instanceIndex, startedIndex}
+)
+
+// schemaVersion is bumped whenever the index layout changes
+const schemaVersion = "2"
+
+// BoltStorage implements Storage with an embedded bbolt database. Records are
+// keyed by ScanRecord.Key, with secondary indexes on instance, status, URL and
+// start time. Metric samples are keyed "recordKey\x00time".
+// The database is opened per operation, so the file lock is only held briefly
+// and several burp-cli processes can share the history.
+type BoltStorage struct {
+	filePath string
+}
+
+// NewBoltStorage creates the database and its buckets if needed
+func NewBoltStorage(filePath string) (*BoltStorage, error) {
+	storage := &BoltStorage{filePath: filePath}
+
+	err := storage.update(func(tx *bolt.Tx) error {
+		for _, name := range schemaBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		if string(tx.Bucket(metaBucket).Get(schemaKey)) != schemaVersion {
+			if err := rebuildIndexes(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(metaBucket).Put(schemaKey, []byte(schemaVersion))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize scan history database: %v", err)
+	}
+
+	return storage, nil
+}
+
+// MigrateJSON imports the records of a legacy scan_history.json once. The
+// JSON file is kept as <name>.migrated for reference.
+func (s *BoltStorage) MigrateJSON(jsonPath string) error {
+	if _, err := os.Stat(jsonPath); err != nil {
+		return nil
+	}
+
+	legacy, err := NewJSONStorage(jsonPath)
+	if err != nil {
+		return fmt.Errorf("failed to read scan history for migration: %v", err)
+	}
+	records, err := legacy.List()
+	if err != nil {
+		return fmt.Errorf("failed to read scan history for migration: %v", err)
+	}
+
+	migrated := false
+	err = s.update(func(tx *bolt.Tx) error {
+		// Another process may have migrated while we were reading
+		if tx.Bucket(metaBucket).Get(migratedKey) != nil {
+			return nil
+		}
+		for _, record := range records {
+			if tx.Bucket(scansBucket).Get([]byte(record.Key())) != nil {
+				continue
+			}
+			if err := putRecord(tx, record); err != nil {
+				return err
+			}
+		}
+		migrated = true
+		return tx.Bucket(metaBucket).Put(migratedKey, []byte(time.Now().Format(time.RFC3339)))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate scan history: %v", err)
+	}
+
+	if migrated {
+		if err := os.Rename(jsonPath, jsonPath+".migrated"); err != nil {
+			return fmt.Errorf("migrated scan history but failed to rename %v: %v", jsonPath, err)
+		}
+	}
+	return nil
+}
+
+// Add stores a new record
+func (s *BoltStorage) Add(record ScanRecord) error {
+	return s.update(func(tx *bolt.Tx) error {
+		if tx.Bucket(scansBucket).Get([]byte(record.Key())) != nil {
+			return fmt.Errorf("scan %s already exists", record.Key())
+		}
+		return putRecord(tx, record)
+	})
+}
+
+// Update applies fn to a stored record and refreshes its index entries
+func (s *BoltStorage) Update(key string, fn func(record *ScanRecord) error) error {
+	return s.update(func(tx *bolt.Tx) error {
+		record, err := getRecord(tx, key)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			return fmt.Errorf("scan %s not found", key)
+		}
+
+		if err := fn(record); err != nil {
+			return err
+		}
+		// Claiming a legacy record moves it to its instance key
+		if record.Key() != key && tx.Bucket(scansBucket).Get([]byte(record.Key())) != nil {
+			return fmt.Errorf("scan %s already exists", record.Key())
+		}
+		if err := deleteRecord(tx, key); err != nil {
+			return err
+		}
+		if record.Key() != key {
+			if err := moveSamples(tx, key, record.Key()); err != nil {
+				return err
+			}
+		}
+		return putRecord(tx, *record)
+	})
+}
+
+// Get returns a record, or nil if the key is unknown
+func (s *BoltStorage) Get(key string) (*ScanRecord, error) {
+	var record *ScanRecord
+	err := s.view(func(tx *bolt.Tx) error {
+		var err error
+		record, err = getRecord(tx, key)
+		return err
+	})
+	return record, err
+}
+
+// Remove deletes a record
+func (s *BoltStorage) Remove(key string) error {
+	return s.update(func(tx *bolt.Tx) error {
+		record, err := getRecord(tx, key)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			return fmt.Errorf("scan %s not found", key)
+		}
+		if err := deleteSamples(tx, key); err != nil {
+			return err
+		}
+		return deleteRecord(tx, key)
+	})
+}
+
+// List returns every record ordered by start time
+func (s *BoltStorage) List() ([]ScanRecord, error) {
+	return s.ListBetween(time.Time{}, time.Time{})
+}
+
+// ListByInstance returns records of an instance ID
+func (s *BoltStorage) ListByInstance(instanceID string) ([]ScanRecord, error) {
+	return s.listIndex(instanceIndex, []byte(instanceID))
+}
+
+// ListByStatus returns records with the given status
+func (s *BoltStorage) ListByStatus(status string) ([]ScanRecord, error) {
+	return s.listIndex(statusIndex, []byte(status))
+}
+
+// ListByURL returns records for the given target URL
+func (s *BoltStorage) ListByURL(url string) ([]ScanRecord, error) {
+	return s.listIndex(urlIndex, []byte(url))
+}
+
+// ListBetween returns records started in [from, to). A zero bound is open.
+func (s *BoltStorage) ListBetween(from, to time.Time) ([]ScanRecord, error) {
+	var records []ScanRecord
+	err := s.view(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(startedIndex).Cursor()
+		for key, _ := cursor.Seek(timeKey(from)); key != nil; key, _ = cursor.Next() {
+			if !to.IsZero() && bytes.Compare(key[:8], timeKey(to)) >= 0 {
+				break
+			}
+			// Skip the 8-byte timestamp and the separator
+			record, err := getRecord(tx, string(key[9:]))
+			if err != nil {
+				return err
+			}
+			if record != nil {
+				records = append(records, *record)
+			}
+		}
+		return nil
+	})
+	return records, err
+}
+
+// GetMeta returns a stored setting, or "" if it is unset
+func (s *BoltStorage) GetMeta(key string) (string, error) {
+	var value string
+	err := s.view(func(tx *bolt.Tx) error {
+		value = string(tx.Bucket(metaBucket).Get([]byte(key)))
+		return nil
+	})
+	return value, err
+}
+
+// SetMeta stores a setting
+func (s *BoltStorage) SetMeta(key, value string) error {
+	return s.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(metaBucket).Put([]byte(key), []byte(value))
+	})
+}
+
+// AddSamples stores metric samples of a record, replacing samples taken at the same time
+func (s *BoltStorage) AddSamples(key string, samples ...MetricSample) error {
+	return s.update(func(tx *bolt.Tx) error {
+		if tx.Bucket(scansBucket).Get([]byte(key)) == nil {
+			return fmt.Errorf("scan %s not found", key)
+		}
+		for _, sample := range samples {
+			data, err := json.Marshal(sample)
+			if err != nil {
+				return fmt.Errorf("failed to marshal metric sample: %v", err)
+			}
+			if err := tx.Bucket(samplesBucket).Put(sampleKey(key, sample.Time), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListSamples returns the metric samples of a record in chronological order
+func (s *BoltStorage) ListSamples(key string) ([]MetricSample, error) {
+	prefix := append([]byte(key), indexSeparator...)
+
+	var samples []MetricSample
+	err := s.view(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(samplesBucket).Cursor()
+		for k, data := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, data = cursor.Next() {
+			var sample MetricSample
+			if err := json.Unmarshal(data, &sample); err != nil {
+				return fmt.Errorf("failed to unmarshal metric sample of scan %s: %v", key, err)
+			}
+			samples = append(samples, sample)
+		}
+		return nil
+	})
+	return samples, err
+}
+
+// listIndex returns the records referenced by an exact-match index entry
+func (s *BoltStorage) listIndex(index, value []byte) ([]ScanRecord, error) {
+	prefix := append(append([]byte{}, value...), indexSeparator...)
+
+	var records []ScanRecord
+	err := s.view(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(index).Cursor()
+		for key, _ := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+			record, err := getRecord(tx, string(key[len(prefix):]))
+			if err != nil {
+				return err
+			}
+			if record != nil {
+				records = append(records, *record)
+			}
+		}
+		return nil
+	})
+	return records, err
+}
+
+// update runs fn in a read-write transaction
+func (s *BoltStorage) update(fn func(tx *bolt.Tx) error) error {
+	db, err := bolt.Open(s.filePath, 0644, &bolt.Options{Timeout: boltLockTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open scan history database: %v", err)
+	}
+	defer db.Close()
+
+	return db.Update(fn)
+}
+
+// view runs fn in a read-only transaction under a shared file lock
+func (s *BoltStorage) view(fn func(tx *bolt.Tx) error) error {
+	db, err := bolt.Open(s.filePath, 0644, &bolt.Options{Timeout: boltLockTimeout, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to open scan history database: %v", err)
+	}
+	defer db.Close()
+
+	return db.View(fn)
+}
+
+// getRecord decodes a record from the scans bucket
+func getRecord(tx *bolt.Tx, key string) (*ScanRecord, error) {
+	data := tx.Bucket(scansBucket).Get([]byte(key))
+	if data == nil {
+		return nil, nil
+	}
+
+	var record ScanRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scan %s: %v", key, err)
+	}
+	return &record, nil
+}
+
+// putRecord stores a record and its index entries
+func putRecord(tx *bolt.Tx, record ScanRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan %s: %v", record.Key(), err)
+	}
+	if err := tx.Bucket(scansBucket).Put([]byte(record.Key()), data); err != nil {
+		return err
+	}
+
+	for index, key := range indexKeys(record.Key(), record) {
+		if err := tx.Bucket([]byte(index)).Put(key, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteRecord removes the record stored under key and its index entries
+func deleteRecord(tx *bolt.Tx, key string) error {
+	record, err := getRecord(tx, key)
+	if err != nil || record == nil {
+		return err
+	}
+	for index, indexKey := range indexKeys(key, *record) {
+		if err := tx.Bucket([]byte(index)).Delete(indexKey); err != nil {
+			return err
+		}
+	}
+	return tx.Bucket(scansBucket).Delete([]byte(key))
+}
+
+// deleteSamples removes every metric sample of a record
+func deleteSamples(tx *bolt.Tx, key string) error {
+	return moveSamples(tx, key, "")
+}
+
+// moveSamples re-keys the metric samples of a record, or deletes them when
+// newKey is empty
+func moveSamples(tx *bolt.Tx, oldKey, newKey string) error {
+	bucket := tx.Bucket(samplesBucket)
+	prefix := append([]byte(oldKey), indexSeparator...)
+
+	// Collect first; the bucket must not change under the cursor
+	var keys, values [][]byte
+	cursor := bucket.Cursor()
+	for k, data := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, data = cursor.Next() {
+		keys = append(keys, append([]byte{}, k...))
+		values = append(values, append([]byte{}, data...))
+	}
+
+	for i, k := range keys {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+		if newKey == "" {
+			continue
+		}
+		moved := append(append([]byte(newKey), indexSeparator...), k[len(prefix):]...)
+		if err := bucket.Put(moved, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuildIndexes recreates every index from the scans bucket
+func rebuildIndexes(tx *bolt.Tx) error {
+	for _, name := range indexBuckets {
+		if err := tx.DeleteBucket(name); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		if _, err := tx.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+
+	return tx.Bucket(scansBucket).ForEach(func(key, data []byte) error {
+		var record ScanRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return fmt.Errorf("failed to unmarshal scan %s: %v", key, err)
+		}
+		for index, indexKey := range indexKeys(string(key), record) {
+			if err := tx.Bucket([]byte(index)).Put(indexKey, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// indexKeys returns "value\x00key" entries for every index of a record
+func indexKeys(key string, record ScanRecord) map[string][]byte {
+	join := func(value []byte) []byte {
+		entry := append(append([]byte{}, value...), indexSeparator...)
+		return append(entry, key...)
+	}
+
+	return map[string][]byte{
+		string(statusIndex):   join([]byte(record.Status)),
+		string(urlIndex):      join([]byte(record.URL)),
+		string(instanceIndex): join([]byte(record.InstanceID())),
+		string(startedIndex):  join(timeKey(record.StartTime)),
+	}
+}
+
+// sampleKey returns the "recordKey\x00time" key of a metric sample
+func sampleKey(key string, t time.Time) []byte {
+	entry := append([]byte(key), indexSeparator...)
+	return append(entry, timeKey(t)...)
+}
+
+// timeKey encodes a time so byte order matches chronological order
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	if t.IsZero() || t.Before(time.Unix(0, 0)) {
+		return key
+	}
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
@@ -0,0 +1,411 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Bundle format identifiers
+const (
+	BundleFormat  = "burp-cli-scan-bundle"
+	BundleVersion = 1
+)
+
+// Conflict strategies of ImportBundle
+const (
+	// MergeCombine keeps the fresher scan state and unites the metadata
+	MergeCombine = "merge"
+	// MergeNewer replaces a record if the imported one was checked later
+	MergeNewer = "newer"
+	// MergeKeep never touches existing records
+	MergeKeep = "keep"
+	// MergeReplace always overwrites existing records
+	MergeReplace = "replace"
+)
+
+// IsValidStrategy reports whether strategy is a known conflict strategy
+func IsValidStrategy(strategy string) bool {
+	switch strategy {
+	case MergeCombine, MergeNewer, MergeKeep, MergeReplace:
+		return true
+	}
+	return false
+}
+
+// Bundle is a portable export of scan records, e.g. to share history with a team
+type Bundle struct {
+	Format     string         `json:"format"`
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	ExportedBy string         `json:"exported_by,omitempty"`
+	Records    []BundleRecord `json:"records"`
+}
+
+// BundleRecord is a scan record with its metric samples
+type BundleRecord struct {
+	ScanRecord
+	Samples []MetricSample `json:"samples,omitempty"`
+}
+
+// ImportResult counts what ImportBundle did, or would do in a dry run
+type ImportResult struct {
+	Added     int
+	Merged    int
+	Replaced  int
+	Unchanged int
+	// Conflicts lists keys whose records describe different scans; they are
+	// only overwritten with MergeReplace
+	Conflicts []string
+}
+
+// NewBundle wraps records for export. Unclaimed legacy records are assigned
+// to the tracker's instance so they stay unique once imported elsewhere.
+func (st *ScanTracker) NewBundle(records []ScanRecord, withSamples bool) (*Bundle, error) {
+	bundle := &Bundle{
+		Format:     BundleFormat,
+		Version:    BundleVersion,
+		ExportedAt: time.Now(),
+		ExportedBy: bundleAuthor(),
+		Records:    []BundleRecord{},
+	}
+
+	for _, record := range records {
+		entry := BundleRecord{ScanRecord: record}
+		if withSamples {
+			samples, err := st.storage.ListSamples(record.Key())
+			if err != nil {
+				return nil, err
+			}
+			entry.Samples = samples
+		}
+		if entry.Instance == "" {
+			entry.Instance = st.instance.Address()
+			entry.KeyHint = st.instance.KeyHint
+		}
+		bundle.Records = append(bundle.Records, entry)
+	}
+	return bundle, nil
+}
+
+// WriteBundle writes a bundle as indented JSON
+func WriteBundle(w io.Writer, bundle *Bundle) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bundle)
+}
+
+// ReadBundle loads and validates a bundle file or a retention archive
+func ReadBundle(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %v", err)
+	}
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		if data, err = readArchiveBundle(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("failed to read archive %s: %v", path, err)
+		}
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle %s: %v", path, err)
+	}
+	if bundle.Format != BundleFormat {
+		return nil, fmt.Errorf("%s is not a burp-cli scan bundle", path)
+	}
+	if bundle.Version > BundleVersion {
+		return nil, fmt.Errorf("%s uses bundle version %d; this burp-cli reads up to version %d", path, bundle.Version, BundleVersion)
+	}
+	return &bundle, nil
+}
+
+// ImportBundle merges the records of a bundle into the history. Records are
+// matched by instance and scan ID. Loopback and unclaimed instances only name
+// a Burp on the exporter's machine, so records of other testers' bundles are
+// qualified with the exporter and never mixed with local scans; unclaimed
+// records are assigned the tracker's address. Nothing is written when dryRun
+// is set.
+func (st *ScanTracker) ImportBundle(bundle *Bundle, strategy string, dryRun bool) (ImportResult, error) {
+	var result ImportResult
+	if !IsValidStrategy(strategy) {
+		return result, fmt.Errorf("unknown conflict strategy %q (use merge, newer, keep or replace)", strategy)
+	}
+
+	author := bundleAuthor()
+	exporter := bundle.ExportedBy
+	if exporter == "" {
+		exporter = "unknown"
+	}
+
+	for _, entry := range bundle.Records {
+		imported := entry.ScanRecord
+		if imported.ScanID == "" {
+			continue
+		}
+		switch {
+		case imported.Origin == author:
+			// A scan of ours that came back through another tester
+			imported.Origin = ""
+		case imported.Origin == "" && bundle.ExportedBy != author && imported.Owner().IsLocal():
+			imported.Origin = exporter
+		}
+		if imported.Instance == "" {
+			imported.Instance = st.instance.Address()
+			imported.KeyHint = st.instance.KeyHint
+		}
+		imported.Tags = normalizeTags(imported.Tags)
+		key := imported.Key()
+
+		existing, err := st.storage.Get(key)
+		if err != nil {
+			return result, err
+		}
+
+		if existing == nil {
+			result.Added++
+			if dryRun {
+				continue
+			}
+			if err := st.storage.Add(imported); err != nil {
+				return result, err
+			}
+			if err := st.importSamples(key, entry.Samples); err != nil {
+				return result, err
+			}
+			continue
+		}
+
+		samples, err := st.newSamples(key, entry.Samples)
+		if err != nil {
+			return result, err
+		}
+		conflict := !sameTarget(*existing, imported)
+		if conflict {
+			result.Conflicts = append(result.Conflicts, key)
+		}
+
+		var merged ScanRecord
+		switch {
+		case strategy == MergeReplace:
+			merged = imported
+			result.Replaced++
+		case conflict || strategy == MergeKeep:
+			result.Unchanged++
+			continue
+		case strategy == MergeNewer:
+			if !imported.LastChecked.After(existing.LastChecked) {
+				result.Unchanged++
+				continue
+			}
+			merged = imported
+			result.Replaced++
+		default:
+			merged = MergeRecords(*existing, imported)
+			if recordsEqual(*existing, merged) && len(samples) == 0 {
+				result.Unchanged++
+				continue
+			}
+			result.Merged++
+		}
+
+		if dryRun {
+			continue
+		}
+		err = st.storage.Update(key, func(record *ScanRecord) error {
+			*record = merged
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+		if err := st.importSamples(key, samples); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// importSamples stores the metric samples of an imported record
+func (st *ScanTracker) importSamples(key string, samples []MetricSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	return st.storage.AddSamples(key, samples...)
+}
+
+// newSamples returns the samples whose time is not stored for key yet
+func (st *ScanTracker) newSamples(key string, samples []MetricSample) ([]MetricSample, error) {
+	if len(samples) == 0 {
+		return nil, nil
+	}
+	stored, err := st.storage.ListSamples(key)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int64]bool, len(stored))
+	for _, sample := range stored {
+		known[sample.Time.UnixNano()] = true
+	}
+	var fresh []MetricSample
+	for _, sample := range samples {
+		if !known[sample.Time.UnixNano()] {
+			fresh = append(fresh, sample)
+		}
+	}
+	return fresh, nil
+}
+
+// MergeRecords combines two copies of the same scan. The scan state (status,
+// completion and final metrics) comes from the finished copy, or else from
+// the one checked last; tags and note lines are united, and the other fields
+// keep the existing value unless it is empty.
+func MergeRecords(existing, imported ScanRecord) ScanRecord {
+	fresh, stale := existing, imported
+	switch {
+	case IsFinalStatus(imported.Status) && !IsFinalStatus(existing.Status):
+		fresh, stale = imported, existing
+	case IsFinalStatus(existing.Status) && !IsFinalStatus(imported.Status):
+	case imported.LastChecked.After(existing.LastChecked):
+		fresh, stale = imported, existing
+	}
+
+	merged := existing
+	merged.Status = fresh.Status
+	merged.LastChecked = fresh.LastChecked
+	merged.CompletedAt = fresh.CompletedAt
+	merged.Metrics = fresh.Metrics
+	merged.SeverityCounts = fresh.SeverityCounts
+	merged.Phases = fresh.Phases
+	if merged.Phases == nil {
+		merged.Phases = stale.Phases
+	}
+	if merged.CompletedAt.IsZero() {
+		merged.CompletedAt = stale.CompletedAt
+	}
+	if merged.Metrics == nil {
+		merged.Metrics = stale.Metrics
+	}
+	if merged.SeverityCounts == nil {
+		merged.SeverityCounts = stale.SeverityCounts
+	}
+
+	if isPlaceholderURL(merged.URL) && !isPlaceholderURL(imported.URL) {
+		merged.URL = imported.URL
+	}
+	if merged.StartTime.IsZero() || (!imported.StartTime.IsZero() && imported.StartTime.Before(merged.StartTime)) {
+		merged.StartTime = imported.StartTime
+	}
+
+	fill := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	fill(&merged.ConfigName, imported.ConfigName)
+	fill(&merged.ScanName, imported.ScanName)
+	fill(&merged.Project, imported.Project)
+	fill(&merged.JSONExport, imported.JSONExport)
+	fill(&merged.HTMLExport, imported.HTMLExport)
+	merged.Exports = append([]string{}, existing.Exports...)
+	for _, file := range imported.Exports {
+		if !containsString(merged.Exports, file) {
+			merged.Exports = append(merged.Exports, file)
+		}
+	}
+	if merged.Request == nil {
+		merged.Request = imported.Request
+	}
+
+	merged.Tags = normalizeTags(append(append([]string{}, existing.Tags...), imported.Tags...))
+	merged.Notes = mergeNotes(existing.Notes, imported.Notes)
+	return merged
+}
+
+// sameTargetTolerance is how far the start times of two copies of one scan
+// may differ, e.g. when one tester recorded it and another synchronized it
+const sameTargetTolerance = 5 * time.Minute
+
+// sameTarget reports whether two records with the same key describe the same
+// scan. Burp reuses scan IDs after a reinstall, so the copies must have been
+// started at about the same time, and differing URLs are a conflict unless
+// one of them was never resolved.
+func sameTarget(a, b ScanRecord) bool {
+	delta := a.StartTime.Sub(b.StartTime)
+	if delta < -sameTargetTolerance || delta > sameTargetTolerance {
+		return false
+	}
+	return a.URL == b.URL || isPlaceholderURL(a.URL) || isPlaceholderURL(b.URL)
+}
+
+// isPlaceholderURL reports whether url is the "scan_<id>" stand-in used when
+// a synchronized scan's target could not be resolved
+func isPlaceholderURL(url string) bool {
+	return url == "" || strings.HasPrefix(url, "scan_")
+}
+
+// mergeNotes appends the lines of other that notes does not contain yet
+func mergeNotes(notes, other string) string {
+	lines := strings.Split(notes, "\n")
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		seen[strings.TrimSpace(line)] = true
+	}
+	for _, line := range strings.Split(other, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// recordsEqual compares records through their stored JSON form
+func recordsEqual(a, b ScanRecord) bool {
+	first, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	second, err := json.Marshal(b)
+	return err == nil && string(first) == string(second)
+}
+
+// bundleAuthor returns user@host of the exporter, as far as it is known
+func bundleAuthor() string {
+	name := ""
+	if current, err := user.Current(); err == nil {
+		name = current.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		if name == "" {
+			return host
+		}
+		return name + "@" + host
+	}
+	return name
+}
+
+// BundleInstances returns the distinct instances of a bundle's records
+func BundleInstances(bundle *Bundle) []string {
+	seen := make(map[string]bool)
+	var instances []string
+	for _, record := range bundle.Records {
+		id := record.InstanceID()
+		if id == "" {
+			id = "unclaimed"
+		}
+		if !seen[id] {
+			seen[id] = true
+			instances = append(instances, id)
+		}
+	}
+	sort.Strings(instances)
+	return instances
+}
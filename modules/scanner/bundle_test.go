@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestImportBundleLocalInstances imports scans of 127.0.0.1:1337 from two
+// testers. Each tester's scan 1 must be kept apart from the other's and from
+// the local scan 1, while a bundle of our own merges into the history.
+func TestImportBundleLocalInstances(t *testing.T) {
+	for _, backend := range []string{BackendJSON, BackendBolt} {
+		t.Run(backend, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "scan_history")
+			tracker := NewScanTrackerWithStorage(openTestStorage(t, backend, path), NewInstance("127.0.0.1", "1337", ""))
+			if err := tracker.AddScan("1", "https://local.example.com", "", ""); err != nil {
+				t.Fatalf("failed to add local scan: %v", err)
+			}
+
+			started := time.Now().Add(-time.Hour)
+			bundleOf := func(author, url string) *Bundle {
+				return &Bundle{
+					Format:     BundleFormat,
+					Version:    BundleVersion,
+					ExportedBy: author,
+					Records: []BundleRecord{{ScanRecord: ScanRecord{
+						ScanID:    "1",
+						URL:       url,
+						StartTime: started,
+						Status:    StatusSucceeded,
+						Instance:  "127.0.0.1:1337",
+					}}},
+				}
+			}
+
+			for _, author := range []string{"alice@laptop", "bob@desktop"} {
+				result, err := tracker.ImportBundle(bundleOf(author, "https://"+author[:3]+".example.com"), MergeCombine, false)
+				if err != nil {
+					t.Fatalf("failed to import bundle of %s: %v", author, err)
+				}
+				if result.Added != 1 || len(result.Conflicts) != 0 {
+					t.Errorf("bundle of %s: added %d with conflicts %v, want 1 added", author, result.Added, result.Conflicts)
+				}
+			}
+
+			records, err := tracker.storage.List()
+			if err != nil {
+				t.Fatalf("failed to list records: %v", err)
+			}
+			if len(records) != 3 {
+				t.Fatalf("got %d records, want 3", len(records))
+			}
+			if local := tracker.GetScanByID("1"); local == nil || local.URL != "https://local.example.com" {
+				t.Errorf("local scan 1 was replaced by an imported one: %+v", local)
+			}
+			imported, err := tracker.storage.Get("alice@laptop/127.0.0.1:1337/1")
+			if err != nil || imported == nil || imported.URL != "https://ali.example.com" {
+				t.Errorf("scan of alice@laptop is not stored under her name: %+v, %v", imported, err)
+			}
+
+			// A bundle of our own merges into the local scans
+			own := bundleOf(bundleAuthor(), "https://local.example.com")
+			own.Records[0].StartTime = tracker.GetScanByID("1").StartTime
+			result, err := tracker.ImportBundle(own, MergeCombine, false)
+			if err != nil {
+				t.Fatalf("failed to import own bundle: %v", err)
+			}
+			if result.Added != 0 || result.Merged != 1 {
+				t.Errorf("own bundle: added %d, merged %d, want 1 merged", result.Added, result.Merged)
+			}
+		})
+	}
+}
+
+// TestSameTarget covers the safety check for records that share a key
+func TestSameTarget(t *testing.T) {
+	started := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	record := func(url string, offset time.Duration) ScanRecord {
+		return ScanRecord{ScanID: "7", URL: url, StartTime: started.Add(offset)}
+	}
+
+	tests := []struct {
+		name string
+		a, b ScanRecord
+		want bool
+	}{
+		{"same scan", record("https://a.example.com", 0), record("https://a.example.com", 0), true},
+		{"synchronized later", record("https://a.example.com", 0), record("https://a.example.com", 2*time.Minute), true},
+		{"different URL", record("https://a.example.com", 0), record("https://b.example.com", 0), false},
+		{"placeholder URL", record("scan_7", 0), record("https://a.example.com", time.Minute), true},
+		{"empty URL", record("", 0), record("https://a.example.com", 0), true},
+		{"placeholder URL, other start", record("scan_7", 0), record("https://a.example.com", 24*time.Hour), false},
+		{"same URL, other start", record("https://a.example.com", 0), record("https://a.example.com", -time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		if got := sameTarget(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: sameTarget = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
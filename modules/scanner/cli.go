@@ -0,0 +1,811 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joanbono/color"
+)
+
+// Defining colors
+var yellow = color.New(color.Bold, color.FgYellow).SprintfFunc()
+var red = color.New(color.Bold, color.FgRed).SprintfFunc()
+var cyan = color.New(color.Bold, color.FgCyan).SprintfFunc()
+var green = color.New(color.Bold, color.FgGreen).SprintfFunc()
+var greenBG = color.New(color.Bold, color.BgGreen, color.FgBlack).SprintfFunc()
+
+// ScansCommand handles the "scans" subcommands that work on the scan history
+type ScansCommand struct {
+	target string
+	port   string
+	apikey string
+}
+
+// NewScansCommand creates a scans command handler. The Burp instance defaults
+// to target:port and can be changed with -t, -p and -k after the subcommand.
+func NewScansCommand(target, port, apikey string) *ScansCommand {
+	return &ScansCommand{target: target, port: port, apikey: apikey}
+}
+
+// HandleScansCommand handles the main scans command dispatch
+func (sc *ScansCommand) HandleScansCommand(args []string) error {
+	if len(args) == 0 {
+		return sc.ShowHelp()
+	}
+
+	switch subcommand := args[0]; subcommand {
+	case "annotate":
+		return sc.HandleAnnotateCommand(args[1:])
+	case "show":
+		return sc.HandleShowCommand(args[1:])
+	case "metrics":
+		return sc.HandleMetricsCommand(args[1:])
+	case "export":
+		return sc.HandleExportCommand(args[1:])
+	case "import":
+		return sc.HandleImportCommand(args[1:])
+	case "retention":
+		return sc.HandleRetentionCommand(args[1:])
+	case "prune":
+		return sc.HandlePruneCommand(args[1:])
+	case "help", "-h", "--help":
+		return sc.ShowHelp()
+	default:
+		fmt.Fprintf(color.Output, "%v Unknown scans command: %s\n", red(" [-] ERROR:"), subcommand)
+		return sc.ShowHelp()
+	}
+}
+
+// HandleAnnotateCommand edits the tags, project and notes of a scan
+func (sc *ScansCommand) HandleAnnotateCommand(args []string) error {
+	var scanID string
+	var annotation Annotation
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			if scanID != "" {
+				return fmt.Errorf("unexpected argument: %s", arg)
+			}
+			scanID = arg
+			continue
+		}
+		if i+1 >= len(args) {
+			return fmt.Errorf("%s requires a value", arg)
+		}
+		value := args[i+1]
+		i++
+
+		switch arg {
+		case "--tag", "--tags":
+			annotation.AddTags = append(annotation.AddTags, SplitTags(value)...)
+		case "--untag":
+			annotation.RemoveTags = append(annotation.RemoveTags, SplitTags(value)...)
+		case "--project":
+			annotation.Project = &value
+		case "--notes":
+			annotation.Notes = &value
+		case "--add-note":
+			annotation.AppendNote = value
+		default:
+			if !sc.parseInstanceArg(arg, value) {
+				return fmt.Errorf("unknown option: %s", arg)
+			}
+		}
+	}
+
+	if scanID == "" {
+		fmt.Fprintf(color.Output, "%v Scan ID required\n", red(" [-] ERROR:"))
+		return sc.ShowHelp()
+	}
+	if annotation.Empty() {
+		fmt.Fprintf(color.Output, "%v Nothing to change. Use --tag, --untag, --project, --notes or --add-note\n", yellow(" [!] WARNING:"))
+		return nil
+	}
+
+	tracker, err := sc.tracker()
+	if err != nil {
+		return err
+	}
+	record, err := tracker.AnnotateScan(scanID, annotation)
+	if err != nil {
+		return fmt.Errorf("failed to annotate scan: %v", err)
+	}
+
+	fmt.Fprintf(color.Output, "%v Scan %v annotated\n", green(" [+] SUCCESS:"), scanID)
+	printMetadata(record)
+	return nil
+}
+
+// HandleShowCommand prints every stored detail of a scan
+func (sc *ScansCommand) HandleShowCommand(args []string) error {
+	var scanID string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			scanID = arg
+			continue
+		}
+		if i+1 >= len(args) || !sc.parseInstanceArg(arg, args[i+1]) {
+			return fmt.Errorf("unknown option: %s", arg)
+		}
+		i++
+	}
+
+	if scanID == "" {
+		fmt.Fprintf(color.Output, "%v Scan ID required\n", red(" [-] ERROR:"))
+		return sc.ShowHelp()
+	}
+
+	tracker, err := sc.tracker()
+	if err != nil {
+		return err
+	}
+	record := tracker.GetScanByID(scanID)
+	if record == nil {
+		return fmt.Errorf("scan ID %s not found on %v", scanID, tracker.Instance())
+	}
+
+	fmt.Fprintf(color.Output, "%v Scan %v\n", cyan(" [i] INFO:"), record.ScanID)
+	fmt.Fprintf(color.Output, "  • URL: %s\n", record.URL)
+	fmt.Fprintf(color.Output, "  • Status: %s\n", record.Status)
+	fmt.Fprintf(color.Output, "  • Instance: %s\n", record.Owner())
+	fmt.Fprintf(color.Output, "  • Started: %s\n", record.StartTime.Format("2006-01-02 15:04:05 MST"))
+	if !record.CompletedAt.IsZero() {
+		fmt.Fprintf(color.Output, "  • Completed: %s (%s)\n", record.CompletedAt.Format("2006-01-02 15:04:05 MST"), record.Duration().Round(time.Second))
+	}
+	if record.ScanName != "" {
+		fmt.Fprintf(color.Output, "  • Scan name: %s\n", record.ScanName)
+	}
+	if record.ConfigName != "" {
+		fmt.Fprintf(color.Output, "  • Configuration: %s\n", record.ConfigName)
+	}
+	printMetadata(record)
+	if len(record.Phases) > 0 {
+		fmt.Fprintf(color.Output, "  • Phases:\n")
+		for _, phase := range record.Phases {
+			marker := ""
+			if phase.Unexpected {
+				marker = " " + yellow("(unexpected)")
+			}
+			fmt.Fprintf(color.Output, "      %s  %s%s\n", phase.At.Format("2006-01-02 15:04:05"), phase.Status, marker)
+		}
+		fmt.Fprintf(color.Output, "  • Time per phase:\n")
+		for _, phase := range record.PhaseDurations(time.Now()) {
+			fmt.Fprintf(color.Output, "      %-14s %s\n", phase.Status+":", phase.Duration.Round(time.Second))
+		}
+	}
+	for _, warning := range record.LifecycleWarnings(time.Now()) {
+		fmt.Fprintf(color.Output, "%v Scan %s: %s\n", yellow(" [!] WARNING:"), record.ScanID, warning)
+	}
+	if summary := record.SeveritySummary(); summary != "" {
+		fmt.Fprintf(color.Output, "  • Issues: %s\n", summary)
+	}
+	if record.JSONExport != "" {
+		fmt.Fprintf(color.Output, "  • JSON export: %s\n", record.JSONExport)
+	}
+	if record.HTMLExport != "" {
+		fmt.Fprintf(color.Output, "  • HTML export: %s\n", record.HTMLExport)
+	}
+
+	if len(record.Metrics) > 0 {
+		fmt.Fprintf(color.Output, "  • Final metrics:\n")
+		names := make([]string, 0, len(record.Metrics))
+		for name := range record.Metrics {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(color.Output, "      %-32s %v\n", name, record.Metrics[name])
+		}
+	}
+
+	if record.Request != nil {
+		data, err := json.MarshalIndent(record.Request, "      ", "  ")
+		if err == nil {
+			fmt.Fprintf(color.Output, "  • Scan request (secrets masked):\n      %s\n", data)
+		}
+	}
+
+	return nil
+}
+
+// HandleMetricsCommand prints the latest metrics of a scan, or their history
+// as sparklines or CSV
+func (sc *ScansCommand) HandleMetricsCommand(args []string) error {
+	var scanID string
+	history, asCSV := false, false
+	width := 60
+	var only []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			if scanID != "" {
+				return fmt.Errorf("unexpected argument: %s", arg)
+			}
+			scanID = arg
+			continue
+		}
+		switch arg {
+		case "--history":
+			history = true
+			continue
+		case "--csv":
+			history, asCSV = true, true
+			continue
+		}
+		if i+1 >= len(args) {
+			return fmt.Errorf("%s requires a value", arg)
+		}
+		value := args[i+1]
+		i++
+
+		switch arg {
+		case "--width":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid width: %s", value)
+			}
+			width = n
+		case "--metric":
+			for _, name := range strings.Split(value, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					only = append(only, name)
+				}
+			}
+		default:
+			if !sc.parseInstanceArg(arg, value) {
+				return fmt.Errorf("unknown option: %s", arg)
+			}
+		}
+	}
+
+	if scanID == "" {
+		fmt.Fprintf(color.Output, "%v Scan ID required\n", red(" [-] ERROR:"))
+		return sc.ShowHelp()
+	}
+
+	tracker, err := sc.tracker()
+	if err != nil {
+		return err
+	}
+	record := tracker.GetScanByID(scanID)
+	if record == nil {
+		return fmt.Errorf("scan ID %s not found on %v", scanID, tracker.Instance())
+	}
+	samples, err := tracker.GetSamples(scanID)
+	if err != nil {
+		return fmt.Errorf("failed to load metric samples: %v", err)
+	}
+
+	if asCSV {
+		return WriteSamplesCSV(os.Stdout, samples)
+	}
+
+	names := SampleMetrics
+	if len(only) > 0 {
+		names = only
+	}
+
+	fmt.Fprintf(color.Output, "%v Metrics of scan %v (%s, %s)\n", cyan(" [i] INFO:"), record.ScanID, record.URL, record.Status)
+
+	if !history {
+		if len(samples) == 0 && len(record.Metrics) == 0 {
+			fmt.Fprintf(color.Output, "%v No metrics recorded for this scan yet\n", yellow(" [!] WARNING:"))
+			return nil
+		}
+		latest := NewMetricSample(record.Status, record.Metrics)
+		if len(samples) > 0 && (record.CompletedAt.IsZero() || len(record.Metrics) == 0) {
+			latest = samples[len(samples)-1]
+		}
+		for _, name := range names {
+			if value, ok := latest.Values[name]; ok {
+				fmt.Fprintf(color.Output, "  • %-28s %s\n", name, formatMetric(value))
+			}
+		}
+		if len(samples) > 0 {
+			fmt.Fprintf(color.Output, "%v %d samples recorded; use --history for sparklines or --csv for the time series\n", cyan(" [i] INFO:"), len(samples))
+		}
+		return nil
+	}
+
+	if len(samples) == 0 {
+		fmt.Fprintf(color.Output, "%v No metric samples recorded for this scan\n", yellow(" [!] WARNING:"))
+		fmt.Fprintf(color.Output, "%v Samples are taken while burp-cli monitors a scan (-a) or the scheduler daemon runs\n", cyan(" [i] INFO:"))
+		return nil
+	}
+
+	first, last := samples[0].Time, samples[len(samples)-1].Time
+	fmt.Fprintf(color.Output, "  • %d samples from %s to %s (%s)\n", len(samples), first.Format("2006-01-02 15:04:05"), last.Format("2006-01-02 15:04:05 MST"), last.Sub(first).Round(time.Second))
+	for _, name := range names {
+		values, ok := Series(samples, name)
+		if !ok {
+			continue
+		}
+		low, high := values[0], values[0]
+		for _, value := range values {
+			low = min(low, value)
+			high = max(high, value)
+		}
+		fmt.Fprintf(color.Output, "  %-28s %s  min %s  max %s  last %s\n", name, Sparkline(values, width),
+			formatMetric(low), formatMetric(high), formatMetric(values[len(values)-1]))
+	}
+	return nil
+}
+
+// HandleExportCommand writes scan records and their metric samples to a bundle
+func (sc *ScansCommand) HandleExportCommand(args []string) error {
+	var query ScanQuery
+	outFile := ""
+	withSamples := true
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--no-samples" {
+			withSamples = false
+			continue
+		}
+		if i+1 >= len(args) {
+			return fmt.Errorf("%s requires a value", arg)
+		}
+		value := args[i+1]
+		i++
+
+		var err error
+		switch arg {
+		case "-o", "--out":
+			outFile = value
+		case "--status":
+			query.Statuses = append(query.Statuses, strings.Split(value, ",")...)
+		case "--since":
+			query.Since, err = ParseTimeBound(value, time.Now())
+		case "--until":
+			query.Until, err = ParseTimeBound(value, time.Now())
+		case "--url":
+			query.URL = value
+		case "--tag", "--tags":
+			query.Tags = append(query.Tags, SplitTags(value)...)
+		case "--project":
+			query.Project = value
+		case "--config":
+			query.Config = value
+		case "--instance":
+			query.Instance = value
+		default:
+			if !sc.parseInstanceArg(arg, value) {
+				return fmt.Errorf("unknown option: %s", arg)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	tracker, err := sc.tracker()
+	if err != nil {
+		return err
+	}
+	records, _, err := tracker.QueryScans(query)
+	if err != nil {
+		return fmt.Errorf("failed to list scans: %v", err)
+	}
+	bundle, err := tracker.NewBundle(records, withSamples)
+	if err != nil {
+		return fmt.Errorf("failed to export scans: %v", err)
+	}
+
+	if outFile == "-" {
+		return WriteBundle(os.Stdout, bundle)
+	}
+	if outFile == "" {
+		outFile = fmt.Sprintf("burp-cli-scans_%s.json", time.Now().Format("20060102_150405"))
+	}
+	file, err := os.Create(outFile)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %v", err)
+	}
+	defer file.Close()
+	if err := WriteBundle(file, bundle); err != nil {
+		return fmt.Errorf("failed to write bundle: %v", err)
+	}
+
+	fmt.Fprintf(color.Output, "%v Exported %d scans to %s\n", green(" [+] SUCCESS:"), len(bundle.Records), outFile)
+	return nil
+}
+
+// HandleImportCommand merges bundles exported by other users into the history
+func (sc *ScansCommand) HandleImportCommand(args []string) error {
+	var files []string
+	strategy := MergeCombine
+	dryRun := false
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			files = append(files, arg)
+			continue
+		}
+		if arg == "--dry-run" {
+			dryRun = true
+			continue
+		}
+		if i+1 >= len(args) {
+			return fmt.Errorf("%s requires a value", arg)
+		}
+		value := args[i+1]
+		i++
+
+		switch arg {
+		case "--strategy", "--on-conflict":
+			strategy = strings.ToLower(value)
+		default:
+			if !sc.parseInstanceArg(arg, value) {
+				return fmt.Errorf("unknown option: %s", arg)
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		fmt.Fprintf(color.Output, "%v Bundle file required\n", red(" [-] ERROR:"))
+		return sc.ShowHelp()
+	}
+	if !IsValidStrategy(strategy) {
+		return fmt.Errorf("unknown conflict strategy %q (use merge, newer, keep or replace)", strategy)
+	}
+
+	tracker, err := sc.tracker()
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Fprintf(color.Output, "%v Dry run: nothing will be written\n", yellow(" [!] WARNING:"))
+	}
+
+	for _, file := range files {
+		bundle, err := ReadBundle(file)
+		if err != nil {
+			return err
+		}
+		result, err := tracker.ImportBundle(bundle, strategy, dryRun)
+		if err != nil {
+			return fmt.Errorf("failed to import %s: %v", file, err)
+		}
+
+		from := ""
+		if bundle.ExportedBy != "" {
+			from = " from " + bundle.ExportedBy
+		}
+		fmt.Fprintf(color.Output, "%v %s%s (%d scans, exported %s)\n", cyan(" [i] INFO:"), file, from, len(bundle.Records), bundle.ExportedAt.Format("2006-01-02 15:04"))
+		fmt.Fprintf(color.Output, "  • Instances: %s\n", strings.Join(BundleInstances(bundle), ", "))
+		fmt.Fprintf(color.Output, "  • Added: %d, merged: %d, replaced: %d, unchanged: %d\n", result.Added, result.Merged, result.Replaced, result.Unchanged)
+		if len(result.Conflicts) > 0 {
+			action := "kept the existing records"
+			if strategy == MergeReplace {
+				action = "replaced by the bundle"
+			}
+			fmt.Fprintf(color.Output, "%v %d scans differ from the tracked scans with the same ID (%s): %s\n", yellow(" [!] WARNING:"), len(result.Conflicts), action, strings.Join(result.Conflicts, ", "))
+		}
+	}
+
+	if !dryRun {
+		fmt.Fprintf(color.Output, "%v Import complete. List every instance with: burp-cli -L --instance all\n", green(" [+] SUCCESS:"))
+	}
+	return nil
+}
+
+// HandleRetentionCommand shows, sets or clears the retention policy
+func (sc *ScansCommand) HandleRetentionCommand(args []string) error {
+	action := "show"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action = args[0]
+		args = args[1:]
+	}
+
+	// The policy covers the whole history; -t/-p/-k are accepted for symmetry
+	var ruleArgs []string
+	for i := 0; i < len(args); i++ {
+		if i+1 < len(args) && sc.parseInstanceArg(args[i], args[i+1]) {
+			i++
+			continue
+		}
+		ruleArgs = append(ruleArgs, args[i])
+	}
+
+	tracker, err := sc.tracker()
+	if err != nil {
+		return err
+	}
+	policy, err := tracker.RetentionPolicy()
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "show":
+	case "set":
+		if len(ruleArgs) == 0 {
+			return fmt.Errorf("nothing to set. Use --keep-last, --keep-days, --keep-high, --no-keep-high, --action or --archive-dir")
+		}
+		if err := parseRetentionArgs(&policy, ruleArgs); err != nil {
+			return err
+		}
+		if err := tracker.SetRetentionPolicy(policy); err != nil {
+			return fmt.Errorf("failed to save retention policy: %v", err)
+		}
+		fmt.Fprintf(color.Output, "%v Retention policy saved\n", green(" [+] SUCCESS:"))
+	case "clear":
+		policy = DefaultRetentionPolicy()
+		if err := tracker.SetRetentionPolicy(policy); err != nil {
+			return fmt.Errorf("failed to save retention policy: %v", err)
+		}
+		fmt.Fprintf(color.Output, "%v Retention policy cleared\n", green(" [+] SUCCESS:"))
+	default:
+		fmt.Fprintf(color.Output, "%v Unknown retention command: %s\n", red(" [-] ERROR:"), action)
+		return sc.ShowHelp()
+	}
+
+	fmt.Fprintf(color.Output, "%v Retention policy: %s\n", cyan(" [i] INFO:"), policy)
+	if policy.Action != RetentionDelete {
+		dir := policy.ArchiveDir
+		if dir == "" {
+			dir = "~/.burp-cli/archive"
+		}
+		fmt.Fprintf(color.Output, "  • Archive directory: %s\n", dir)
+	}
+	if policy.HasRules() {
+		fmt.Fprintf(color.Output, "%v Preview with: burp-cli scans prune --dry-run\n", cyan(" [i] INFO:"))
+	}
+	return nil
+}
+
+// HandlePruneCommand applies the retention policy to the history and exports
+func (sc *ScansCommand) HandlePruneCommand(args []string) error {
+	dryRun := false
+	instance := ""
+	var ruleArgs []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--dry-run":
+			dryRun = true
+			continue
+		case "--keep-high", "--no-keep-high":
+			ruleArgs = append(ruleArgs, arg)
+			continue
+		}
+		if i+1 >= len(args) {
+			return fmt.Errorf("%s requires a value", arg)
+		}
+		value := args[i+1]
+		i++
+
+		switch arg {
+		case "--instance":
+			instance = value
+		case "--keep-last", "--keep-days", "--action", "--archive-dir":
+			ruleArgs = append(ruleArgs, arg, value)
+		default:
+			if !sc.parseInstanceArg(arg, value) {
+				return fmt.Errorf("unknown option: %s", arg)
+			}
+		}
+	}
+
+	tracker, err := sc.tracker()
+	if err != nil {
+		return err
+	}
+	policy, err := tracker.RetentionPolicy()
+	if err != nil {
+		return err
+	}
+	if err := parseRetentionArgs(&policy, ruleArgs); err != nil {
+		return err
+	}
+	if !policy.HasRules() {
+		fmt.Fprintf(color.Output, "%v No retention rules. Set them with: burp-cli scans retention set --keep-last 5 --keep-days 90\n", yellow(" [!] WARNING:"))
+		return nil
+	}
+
+	records, _, err := tracker.QueryScans(ScanQuery{Instance: instance})
+	if err != nil {
+		return fmt.Errorf("failed to list scans: %v", err)
+	}
+	expired := policy.Expired(records, time.Now())
+
+	fmt.Fprintf(color.Output, "%v Retention policy: %s\n", cyan(" [i] INFO:"), policy)
+	if len(expired) == 0 {
+		fmt.Fprintf(color.Output, "%v No scans expired (%d checked)\n", green(" [+] SUCCESS:"), len(records))
+		return nil
+	}
+
+	for _, record := range expired {
+		fmt.Fprintf(color.Output, "  • %-6s %s  %-10s %s (%d exports)\n", record.ScanID, record.StartTime.Format("2006-01-02"), record.Status, record.URL, len(record.ExportFiles()))
+	}
+
+	verb, done := RetentionArchive, "Archived"
+	if policy.Action == RetentionDelete {
+		verb, done = RetentionDelete, "Deleted"
+	}
+	result, err := tracker.Prune(expired, policy, dryRun)
+	if dryRun {
+		fmt.Fprintf(color.Output, "%v Dry run: would %s %d of %d scans and %d export files (%s)\n", yellow(" [!] WARNING:"),
+			verb, result.Records, len(records), len(result.Files), formatBytes(result.Bytes))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to prune scans: %v", err)
+	}
+
+	fmt.Fprintf(color.Output, "%v %s %d scans and %d export files (%s)\n", green(" [+] SUCCESS:"), done, result.Records, len(result.Files), formatBytes(result.Bytes))
+	if result.Archive != "" {
+		fmt.Fprintf(color.Output, "%v Archive: %s (restore with: burp-cli scans import %s)\n", cyan(" [i] INFO:"), result.Archive, result.Archive)
+	}
+	return nil
+}
+
+// parseRetentionArgs applies retention rule options to policy
+func parseRetentionArgs(policy *RetentionPolicy, args []string) error {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--keep-high":
+			policy.KeepHighSeverity = true
+			continue
+		case "--no-keep-high":
+			policy.KeepHighSeverity = false
+			continue
+		}
+		if i+1 >= len(args) {
+			return fmt.Errorf("%s requires a value", arg)
+		}
+		value := args[i+1]
+		i++
+
+		var err error
+		switch arg {
+		case "--keep-last":
+			policy.KeepLast, err = strconv.Atoi(value)
+		case "--keep-days":
+			policy.KeepDays, err = strconv.Atoi(value)
+		case "--action":
+			policy.Action = strings.ToLower(value)
+		case "--archive-dir":
+			policy.ArchiveDir = value
+			if value != "" {
+				policy.ArchiveDir, err = filepath.Abs(value)
+			}
+		default:
+			return fmt.Errorf("unknown option: %s", arg)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %s", arg, value)
+		}
+	}
+	return policy.Validate()
+}
+
+// formatBytes renders a size in B, KB or MB
+func formatBytes(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", size)
+}
+
+// ShowHelp displays help for scans commands
+func (sc *ScansCommand) ShowHelp() error {
+	fmt.Fprintf(color.Output, "%v burp-cli Scans Commands:\n", cyan(" [i] INFO:"))
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "%v Available Commands:\n", greenBG(" [*] COMMANDS:"))
+	fmt.Fprintf(color.Output, "  • burp-cli scans show <id>                   - Show metadata, final metrics and scan request\n")
+	fmt.Fprintf(color.Output, "  • burp-cli scans annotate <id> [options]     - Edit tags, project and notes\n")
+	fmt.Fprintf(color.Output, "  • burp-cli scans metrics <id> [options]      - Show latest metrics or their history\n")
+	fmt.Fprintf(color.Output, "  • burp-cli scans export [options]            - Write scans to a portable bundle\n")
+	fmt.Fprintf(color.Output, "  • burp-cli scans import <bundle>... [options] - Merge bundles from other users\n")
+	fmt.Fprintf(color.Output, "  • burp-cli scans retention [set|clear]       - Show or change the retention policy\n")
+	fmt.Fprintf(color.Output, "  • burp-cli scans prune [--dry-run]           - Archive or delete expired scans and exports\n")
+	fmt.Fprintf(color.Output, "  • burp-cli scans help                        - Show this help\n")
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "%v Annotate Options:\n", greenBG(" [*] OPTIONS:"))
+	fmt.Fprintf(color.Output, "  --tag a,b           Add tags\n")
+	fmt.Fprintf(color.Output, "  --untag a,b         Remove tags\n")
+	fmt.Fprintf(color.Output, "  --project NAME      Set the project or engagement (empty to clear)\n")
+	fmt.Fprintf(color.Output, "  --notes TEXT        Replace the notes (empty to clear)\n")
+	fmt.Fprintf(color.Output, "  --add-note TEXT     Append a line to the notes\n")
+	fmt.Fprintf(color.Output, "  -t, -p, -k          Burp address, port and API key of the scan's instance\n")
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "%v Metrics Options:\n", greenBG(" [*] OPTIONS:"))
+	fmt.Fprintf(color.Output, "  --history           Show a sparkline per metric over all samples\n")
+	fmt.Fprintf(color.Output, "  --csv               Write the samples as CSV to stdout\n")
+	fmt.Fprintf(color.Output, "  --metric a,b        Only show these metrics\n")
+	fmt.Fprintf(color.Output, "  --width N           Sparkline width in characters (default: 60)\n")
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "%v Export Options:\n", greenBG(" [*] OPTIONS:"))
+	fmt.Fprintf(color.Output, "  -o, --out FILE      Bundle file, - for stdout (default: burp-cli-scans_<time>.json)\n")
+	fmt.Fprintf(color.Output, "  --status, --since, --until, --url, --tags, --project, --config\n")
+	fmt.Fprintf(color.Output, "                      Same filters as -L\n")
+	fmt.Fprintf(color.Output, "  --instance GLOB     Export other instances too (all for the whole history)\n")
+	fmt.Fprintf(color.Output, "  --no-samples        Leave out the metric samples\n")
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "%v Import Options:\n", greenBG(" [*] OPTIONS:"))
+	fmt.Fprintf(color.Output, "  --strategy S        Conflict handling for scans already in the history:\n")
+	fmt.Fprintf(color.Output, "                      merge (default) keeps the latest state and unites tags and notes,\n")
+	fmt.Fprintf(color.Output, "                      newer takes the copy checked last, keep or replace\n")
+	fmt.Fprintf(color.Output, "  --dry-run           Report what would change without writing\n")
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "%v Retention Options (set, or override for one prune):\n", greenBG(" [*] OPTIONS:"))
+	fmt.Fprintf(color.Output, "  --keep-last N       Keep the newest N scans of every target URL\n")
+	fmt.Fprintf(color.Output, "  --keep-days M       Keep scans started in the last M days\n")
+	fmt.Fprintf(color.Output, "  --keep-high         Always keep scans with high-severity or uncounted issues (default)\n")
+	fmt.Fprintf(color.Output, "  --no-keep-high      Let high-severity scans expire too\n")
+	fmt.Fprintf(color.Output, "  --action A          archive (default) or delete expired scans and exports\n")
+	fmt.Fprintf(color.Output, "  --archive-dir DIR   Where archives go (default: ~/.burp-cli/archive)\n")
+	fmt.Fprintf(color.Output, "  --dry-run           With prune: list what would expire\n")
+	fmt.Fprintf(color.Output, "  --instance GLOB     With prune: include other instances (all for the whole history)\n")
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "%v Examples:\n", greenBG(" [*] EXAMPLES:"))
+	fmt.Fprintf(color.Output, "  burp-cli scans annotate 12 --tag prod,retest --project \"ACME Q3\"\n")
+	fmt.Fprintf(color.Output, "  burp-cli scans annotate 12 --add-note \"Login flow out of scope\" -p 8090\n")
+	fmt.Fprintf(color.Output, "  burp-cli scans metrics 12 --history\n")
+	fmt.Fprintf(color.Output, "  burp-cli scans metrics 12 --csv > scan12-metrics.csv\n")
+	fmt.Fprintf(color.Output, "  burp-cli scans export --project \"ACME Q3\" -o alice.json\n")
+	fmt.Fprintf(color.Output, "  burp-cli scans import alice.json bob.json --dry-run\n")
+	fmt.Fprintf(color.Output, "  burp-cli scans retention set --keep-last 5 --keep-days 90\n")
+	fmt.Fprintf(color.Output, "  burp-cli scans prune --dry-run\n")
+
+	return nil
+}
+
+// parseInstanceArg applies the -t/-p/-k options shared by all scans commands
+func (sc *ScansCommand) parseInstanceArg(arg, value string) bool {
+	switch arg {
+	case "-t", "--target":
+		sc.target = value
+	case "-p", "--port":
+		sc.port = value
+	case "-k", "--key":
+		sc.apikey = value
+	default:
+		return false
+	}
+	return true
+}
+
+// tracker opens the scan history of the selected instance
+func (sc *ScansCommand) tracker() (*ScanTracker, error) {
+	tracker, err := NewScanTracker(NewInstance(sc.target, sc.port, sc.apikey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize scan tracker: %v", err)
+	}
+	return tracker, nil
+}
+
+// formatMetric prints whole numbers without decimals
+func formatMetric(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// printMetadata prints the tags, project and notes of a record
+func printMetadata(record *ScanRecord) {
+	if record.Project != "" {
+		fmt.Fprintf(color.Output, "  • Project: %s\n", record.Project)
+	}
+	if len(record.Tags) > 0 {
+		fmt.Fprintf(color.Output, "  • Tags: %s\n", strings.Join(record.Tags, ", "))
+	}
+	if record.Notes != "" {
+		fmt.Fprintf(color.Output, "  • Notes: %s\n", strings.ReplaceAll(record.Notes, "\n", "\n           "))
+	}
+}
@@ -0,0 +1,138 @@
+package scanner
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Discovery defaults
+const (
+	DefaultDiscoveryMisses      = 10
+	DefaultDiscoveryConcurrency = 8
+)
+
+// RemoteScan is a scan found on the Burp instance
+type RemoteScan struct {
+	ID     int
+	Status string
+	URL    string
+}
+
+// ProbeFunc fetches one scan from Burp. It returns nil without an error when
+// the ID does not exist; an error aborts the discovery.
+type ProbeFunc func(id int) (*RemoteScan, error)
+
+// DiscoveryOptions controls how far and how fast IDs are probed
+type DiscoveryOptions struct {
+	// Start is the first ID to probe
+	Start int
+	// Misses is the number of consecutive unknown IDs that ends the discovery
+	Misses int
+	// Concurrency limits the number of probes in flight
+	Concurrency int
+}
+
+// Discover probes IDs upwards from opts.Start in windows of opts.Concurrency
+// parallel requests and stops once opts.Misses consecutive IDs do not exist.
+// Found scans are returned in ID order.
+func Discover(probe ProbeFunc, opts DiscoveryOptions) ([]RemoteScan, error) {
+	if opts.Start < 1 {
+		opts.Start = 1
+	}
+	if opts.Misses < 1 {
+		opts.Misses = DefaultDiscoveryMisses
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = DefaultDiscoveryConcurrency
+	}
+
+	var found []RemoteScan
+	misses := 0
+	for next := opts.Start; misses < opts.Misses; next += opts.Concurrency {
+		ids := make([]int, opts.Concurrency)
+		for i := range ids {
+			ids[i] = next + i
+		}
+
+		results, err := ProbeAll(probe, ids, opts.Concurrency)
+		if err != nil {
+			return found, err
+		}
+
+		// Count consecutive misses in ID order; scans found after a gap
+		// in the same window are still kept
+		for _, id := range ids {
+			if scan, ok := results[id]; ok {
+				found = append(found, scan)
+				misses = 0
+			} else {
+				misses++
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// ProbeAll probes the given IDs with at most concurrency requests in flight
+// and returns the scans that exist, keyed by ID
+func ProbeAll(probe ProbeFunc, ids []int, concurrency int) (map[int]RemoteScan, error) {
+	if concurrency < 1 {
+		concurrency = DefaultDiscoveryConcurrency
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mutex    sync.Mutex
+		firstErr error
+		results  = make(map[int]RemoteScan)
+		slots    = make(chan struct{}, concurrency)
+	)
+	for _, id := range ids {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(id int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			scan, err := probe(id)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			if scan != nil {
+				results[id] = *scan
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	return results, firstErr
+}
+
+// HighestScanID returns the largest numeric scan ID among records
+func HighestScanID(records []ScanRecord) int {
+	highest := 0
+	for _, record := range records {
+		if id, err := strconv.Atoi(record.ScanID); err == nil && id > highest {
+			highest = id
+		}
+	}
+	return highest
+}
+
+// SortedIDs returns the keys of a probe result in ascending order
+func SortedIDs(results map[int]RemoteScan) []int {
+	ids := make([]int, 0, len(results))
+	for id := range results {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"strings"
+)
+
+// Instance identifies the Burp Suite REST API that owns a scan by its address.
+// Scan IDs are small per-instance integers, so records are only unique per
+// instance.
+type Instance struct {
+	Host string
+	Port string
+	// KeyHint is a short hash of the API key. It tells which key a scan was
+	// started with but is not part of the identity, so rotating the key keeps
+	// the history. It is empty when no key is used.
+	KeyHint string
+	// Origin names the tester whose machine a local address refers to. It is
+	// set for scans imported from other testers' bundles and empty for
+	// instances reached from this machine.
+	Origin string
+}
+
+// NewInstance describes the Burp REST API at host:port. The API key is only
+// used to derive the key hint and is never stored.
+func NewInstance(host, port, apikey string) Instance {
+	instance := Instance{
+		Host: strings.ToLower(strings.TrimSpace(host)),
+		Port: strings.TrimSpace(port),
+	}
+	if apikey != "" {
+		sum := sha256.Sum256([]byte(apikey))
+		instance.KeyHint = hex.EncodeToString(sum[:])[:12]
+	}
+	return instance
+}
+
+// Address returns host:port
+func (i Instance) Address() string {
+	if i.Host == "" && i.Port == "" {
+		return ""
+	}
+	return net.JoinHostPort(i.Host, i.Port)
+}
+
+// ID returns the identity records are keyed by, which is the address,
+// qualified with the origin of imported local instances
+func (i Instance) ID() string {
+	if i.Origin != "" {
+		return i.Origin + "/" + i.Address()
+	}
+	return i.Address()
+}
+
+// IsLocal reports whether the address only identifies a Burp on the machine
+// it is used from: loopback addresses, and unclaimed records without one
+func (i Instance) IsLocal() bool {
+	if i.Host == "" || i.Host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(i.Host)
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
+
+// String renders the instance for display
+func (i Instance) String() string {
+	if i.Address() == "" {
+		return "-"
+	}
+	if i.KeyHint == "" {
+		return i.ID()
+	}
+	return i.ID() + " (key " + i.KeyHint + ")"
+}
+
+// Owns reports whether a record was created on this instance
+func (i Instance) Owns(record ScanRecord) bool {
+	return record.InstanceID() == i.ID()
+}
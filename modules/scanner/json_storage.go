@@ -0,0 +1,355 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// JSONStorage implements Storage with a single JSON file. It is safe for
+// concurrent use by goroutines and by several burp-cli processes: every
+// change takes an advisory lock on a sidecar lock file, reloads the history
+// from disk, applies the change and atomically replaces the file.
+type JSONStorage struct {
+	filePath string
+	mutex    sync.Mutex
+}
+
+// jsonHistory is the on-disk layout of scan_history.json
+type jsonHistory struct {
+	Records []ScanRecord              `json:"records"`
+	Meta    map[string]string         `json:"meta,omitempty"`
+	Samples map[string][]MetricSample `json:"samples,omitempty"`
+}
+
+// NewJSONStorage creates a JSON storage backed by filePath
+func NewJSONStorage(filePath string) (*JSONStorage, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %v", err)
+	}
+
+	storage := &JSONStorage{filePath: filePath}
+
+	// Validate an existing file up front
+	if _, err := storage.List(); err != nil {
+		return nil, err
+	}
+
+	return storage, nil
+}
+
+// Add stores a new record
+func (s *JSONStorage) Add(record ScanRecord) error {
+	return s.update(func(history *jsonHistory) error {
+		for _, existing := range history.Records {
+			if existing.Key() == record.Key() {
+				return fmt.Errorf("scan %s already exists", record.Key())
+			}
+		}
+		history.Records = append(history.Records, record)
+		return nil
+	})
+}
+
+// Update applies fn to a stored record
+func (s *JSONStorage) Update(key string, fn func(record *ScanRecord) error) error {
+	return s.update(func(history *jsonHistory) error {
+		for i := range history.Records {
+			if history.Records[i].Key() != key {
+				continue
+			}
+			updated := history.Records[i]
+			if err := fn(&updated); err != nil {
+				return err
+			}
+			if updated.Key() != key {
+				for _, existing := range history.Records {
+					if existing.Key() == updated.Key() {
+						return fmt.Errorf("scan %s already exists", updated.Key())
+					}
+				}
+			}
+			history.Records[i] = updated
+			if updated.Key() != key && history.Samples[key] != nil {
+				history.Samples[updated.Key()] = history.Samples[key]
+				delete(history.Samples, key)
+			}
+			return nil
+		}
+		return fmt.Errorf("scan %s not found", key)
+	})
+}
+
+// Get returns a record, or nil if the key is unknown
+func (s *JSONStorage) Get(key string) (*ScanRecord, error) {
+	records, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.Key() == key {
+			return &record, nil
+		}
+	}
+	return nil, nil
+}
+
+// Remove deletes a record
+func (s *JSONStorage) Remove(key string) error {
+	return s.update(func(history *jsonHistory) error {
+		for i := range history.Records {
+			if history.Records[i].Key() == key {
+				history.Records = append(history.Records[:i], history.Records[i+1:]...)
+				delete(history.Samples, key)
+				return nil
+			}
+		}
+		return fmt.Errorf("scan %s not found", key)
+	})
+}
+
+// List returns every record ordered by start time
+func (s *JSONStorage) List() ([]ScanRecord, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	unlock, err := s.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	history, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(history.Records, func(i, j int) bool {
+		return history.Records[i].StartTime.Before(history.Records[j].StartTime)
+	})
+	return history.Records, nil
+}
+
+// ListByInstance returns records of an instance ID
+func (s *JSONStorage) ListByInstance(instanceID string) ([]ScanRecord, error) {
+	return s.filter(func(record ScanRecord) bool {
+		return record.InstanceID() == instanceID
+	})
+}
+
+// ListByStatus returns records with the given status
+func (s *JSONStorage) ListByStatus(status string) ([]ScanRecord, error) {
+	return s.filter(func(record ScanRecord) bool {
+		return record.Status == status
+	})
+}
+
+// ListByURL returns records for the given target URL
+func (s *JSONStorage) ListByURL(url string) ([]ScanRecord, error) {
+	return s.filter(func(record ScanRecord) bool {
+		return record.URL == url
+	})
+}
+
+// ListBetween returns records started in [from, to). A zero bound is open.
+func (s *JSONStorage) ListBetween(from, to time.Time) ([]ScanRecord, error) {
+	return s.filter(func(record ScanRecord) bool {
+		return !record.StartTime.Before(from) && (to.IsZero() || record.StartTime.Before(to))
+	})
+}
+
+// GetMeta returns a stored setting, or "" if it is unset
+func (s *JSONStorage) GetMeta(key string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	unlock, err := s.lock(false)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	history, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	return history.Meta[key], nil
+}
+
+// SetMeta stores a setting
+func (s *JSONStorage) SetMeta(key, value string) error {
+	return s.update(func(history *jsonHistory) error {
+		if history.Meta == nil {
+			history.Meta = make(map[string]string)
+		}
+		history.Meta[key] = value
+		return nil
+	})
+}
+
+// AddSamples stores metric samples of a record, replacing samples taken at the same time
+func (s *JSONStorage) AddSamples(key string, samples ...MetricSample) error {
+	return s.update(func(history *jsonHistory) error {
+		for _, record := range history.Records {
+			if record.Key() != key {
+				continue
+			}
+			if history.Samples == nil {
+				history.Samples = make(map[string][]MetricSample)
+			}
+			stored := history.Samples[key]
+			index := make(map[int64]int, len(stored))
+			for i, sample := range stored {
+				index[sample.Time.UnixNano()] = i
+			}
+			for _, sample := range samples {
+				if i, ok := index[sample.Time.UnixNano()]; ok {
+					stored[i] = sample
+					continue
+				}
+				index[sample.Time.UnixNano()] = len(stored)
+				stored = append(stored, sample)
+			}
+			history.Samples[key] = stored
+			return nil
+		}
+		return fmt.Errorf("scan %s not found", key)
+	})
+}
+
+// ListSamples returns the metric samples of a record in chronological order
+func (s *JSONStorage) ListSamples(key string) ([]MetricSample, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	unlock, err := s.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	history, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	samples := history.Samples[key]
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].Time.Before(samples[j].Time)
+	})
+	return samples, nil
+}
+
+// filter lists the records matching fn; the JSON file has no indexes
+func (s *JSONStorage) filter(fn func(record ScanRecord) bool) ([]ScanRecord, error) {
+	records, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []ScanRecord
+	for _, record := range records {
+		if fn(record) {
+			matched = append(matched, record)
+		}
+	}
+	return matched, nil
+}
+
+// update reloads the history under an exclusive lock, applies fn and saves
+// the result, so concurrent writers never overwrite each other's records
+func (s *JSONStorage) update(fn func(history *jsonHistory) error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	unlock, err := s.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	history, err := s.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(history); err != nil {
+		return err
+	}
+	return s.save(history)
+}
+
+// lock takes the advisory lock on the sidecar lock file. The history file
+// itself is replaced on every save, so it cannot carry the lock.
+func (s *JSONStorage) lock(exclusive bool) (func(), error) {
+	unlock, err := LockSidecar(s.filePath, exclusive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock scan history: %v", err)
+	}
+	return unlock, nil
+}
+
+// save writes the scan records to a temporary file and renames it over the
+// history, so readers never see a partially written file
+func (s *JSONStorage) save(history *jsonHistory) error {
+	if history.Records == nil {
+		history.Records = []ScanRecord{}
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan records: %v", err)
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(s.filePath), filepath.Base(s.filePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	tempPath := tempFile.Name()
+
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write scan records: %v", err)
+	}
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write scan records: %v", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write scan records: %v", err)
+	}
+	if err := os.Chmod(tempPath, 0644); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write scan records: %v", err)
+	}
+
+	if err := os.Rename(tempPath, s.filePath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to replace scan records: %v", err)
+	}
+
+	return nil
+}
+
+// load reads the scan records from disk. A missing file is an empty history.
+func (s *JSONStorage) load() (*jsonHistory, error) {
+	history := &jsonHistory{}
+
+	data, err := os.ReadFile(s.filePath)
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scan records: %v", err)
+	}
+
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scan records: %v", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,190 @@
+package scanner
+
+import (
+	"fmt"
+	"time"
+)
+
+// Scan states. Burp reports all but StatusRunning, which the tracker uses
+// until the first status of a new scan is known.
+const (
+	StatusRunning      = "running"
+	StatusInitializing = "initializing"
+	StatusCrawling     = "crawling"
+	StatusAuditing     = "auditing"
+	StatusPaused       = "paused"
+	StatusSucceeded    = "succeeded"
+	StatusFailed       = "failed"
+	StatusCancelled    = "cancelled"
+)
+
+// InitializingWarnAfter is how long a scan may initialize before it is flagged
+const InitializingWarnAfter = 10 * time.Minute
+
+// transitions lists the states each state may move to. Final states have none.
+var transitions = map[string][]string{
+	StatusRunning:      {StatusInitializing, StatusCrawling, StatusAuditing, StatusPaused, StatusSucceeded, StatusFailed, StatusCancelled},
+	StatusInitializing: {StatusCrawling, StatusAuditing, StatusPaused, StatusSucceeded, StatusFailed, StatusCancelled},
+	StatusCrawling:     {StatusAuditing, StatusPaused, StatusSucceeded, StatusFailed, StatusCancelled},
+	StatusAuditing:     {StatusCrawling, StatusPaused, StatusSucceeded, StatusFailed, StatusCancelled},
+	StatusPaused:       {StatusInitializing, StatusCrawling, StatusAuditing, StatusSucceeded, StatusFailed, StatusCancelled},
+	StatusSucceeded:    {},
+	StatusFailed:       {},
+	StatusCancelled:    {},
+}
+
+// IsKnownStatus reports whether status is part of the lifecycle model
+func IsKnownStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// CanTransition reports whether a scan may move from one state to another.
+// Unknown states allow any transition so Burp stays authoritative.
+func CanTransition(from, to string) bool {
+	allowed, ok := transitions[from]
+	if !ok || !IsKnownStatus(to) {
+		return true
+	}
+	for _, state := range allowed {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
+// PhaseChange records when the tracker first saw a scan in a state
+type PhaseChange struct {
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+	// Unexpected marks unknown states and transitions the model does not allow
+	Unexpected bool `json:"unexpected,omitempty"`
+}
+
+// Transition describes a status update applied by TransitionScan
+type Transition struct {
+	From    string
+	To      string
+	Changed bool
+	// Unknown is set when Burp reported a state outside the lifecycle model
+	Unknown bool
+	// Invalid is set when the model does not allow From -> To
+	Invalid bool
+}
+
+// PhaseDuration is the total time a scan spent in one state
+type PhaseDuration struct {
+	Status   string
+	Duration time.Duration
+}
+
+// TransitionScan moves a scan to status and records the phase change. The
+// first terminal status also records the completion time. Unknown states
+// and disallowed transitions are stored and reported, not rejected.
+func (st *ScanTracker) TransitionScan(scanID, status string) (Transition, error) {
+	var transition Transition
+	err := st.UpdateScan(scanID, func(record *ScanRecord) {
+		transition = record.applyStatus(status, time.Now())
+	})
+	return transition, err
+}
+
+// applyStatus changes the status of a record and appends the phase change
+func (r *ScanRecord) applyStatus(status string, now time.Time) Transition {
+	transition := Transition{From: r.Status, To: status}
+	if status == "" || status == r.Status {
+		return transition
+	}
+
+	transition.Changed = true
+	transition.Unknown = !IsKnownStatus(status)
+	transition.Invalid = !CanTransition(r.Status, status)
+
+	// Records created before phases were tracked start with their current state
+	if len(r.Phases) == 0 && r.Status != "" {
+		r.Phases = append(r.Phases, PhaseChange{Status: r.Status, At: r.StartTime})
+	}
+	r.Phases = append(r.Phases, PhaseChange{
+		Status:     status,
+		At:         now,
+		Unexpected: transition.Unknown || transition.Invalid,
+	})
+
+	r.Status = status
+	if IsFinalStatus(status) && r.CompletedAt.IsZero() {
+		r.CompletedAt = now
+	}
+	return transition
+}
+
+// PhaseDurations sums the time spent in each non-final state in order of
+// first appearance, leaving out states shorter than a second. The current
+// phase of an unfinished scan runs until now.
+func (r ScanRecord) PhaseDurations(now time.Time) []PhaseDuration {
+	var durations []PhaseDuration
+	index := make(map[string]int)
+	for i, phase := range r.Phases {
+		if IsFinalStatus(phase.Status) {
+			continue
+		}
+		end := now
+		if i+1 < len(r.Phases) {
+			end = r.Phases[i+1].At
+		} else if !r.CompletedAt.IsZero() {
+			end = r.CompletedAt
+		}
+
+		if _, ok := index[phase.Status]; !ok {
+			index[phase.Status] = len(durations)
+			durations = append(durations, PhaseDuration{Status: phase.Status})
+		}
+		if end.After(phase.At) {
+			durations[index[phase.Status]].Duration += end.Sub(phase.At)
+		}
+	}
+
+	// Drop states left before the next poll, like the initial "running"
+	var spent []PhaseDuration
+	for _, phase := range durations {
+		if phase.Duration >= time.Second {
+			spent = append(spent, phase)
+		}
+	}
+	return spent
+}
+
+// CurrentPhaseSince returns when the scan entered its current state
+func (r ScanRecord) CurrentPhaseSince() time.Time {
+	if n := len(r.Phases); n > 0 && r.Phases[n-1].Status == r.Status {
+		return r.Phases[n-1].At
+	}
+	return r.StartTime
+}
+
+// LifecycleWarnings flags paused and long-initializing scans, unknown states
+// and unexpected transitions
+func (r ScanRecord) LifecycleWarnings(now time.Time) []string {
+	var warnings []string
+	since := now.Sub(r.CurrentPhaseSince()).Round(time.Second)
+
+	switch {
+	case r.Status == StatusPaused:
+		warnings = append(warnings, fmt.Sprintf("paused for %s; resume it in Burp", since))
+	case r.Status == StatusInitializing && since >= InitializingWarnAfter:
+		warnings = append(warnings, fmt.Sprintf("initializing for %s; check the target and scan configuration", since))
+	case len(r.Phases) == 0 && !IsKnownStatus(r.Status):
+		warnings = append(warnings, fmt.Sprintf("unknown Burp status %q", r.Status))
+	}
+
+	for i, phase := range r.Phases {
+		switch {
+		case !phase.Unexpected:
+		case !IsKnownStatus(phase.Status):
+			warnings = append(warnings, fmt.Sprintf("unknown Burp status %q at %s", phase.Status, phase.At.Format("2006-01-02 15:04")))
+		case i > 0:
+			warnings = append(warnings, fmt.Sprintf("unexpected transition %s -> %s at %s", r.Phases[i-1].Status, phase.Status, phase.At.Format("2006-01-02 15:04")))
+		}
+	}
+	return warnings
+}
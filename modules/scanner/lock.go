@@ -0,0 +1,25 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+)
+
+// LockSidecar takes an advisory lock on the sidecar file path+".lock" and
+// returns the function releasing it. Files that are atomically replaced on
+// every save cannot carry the lock themselves, so all processes writing them
+// lock the sidecar instead.
+func LockSidecar(path string, exclusive bool) (func(), error) {
+	file, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s.lock: %v", path, err)
+	}
+	if err := lockFile(file, exclusive); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return func() {
+		unlockFile(file)
+		file.Close()
+	}, nil
+}
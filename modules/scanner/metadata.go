@@ -0,0 +1,110 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IsFinalStatus reports whether a Burp scan status is terminal
+func IsFinalStatus(status string) bool {
+	switch status {
+	case StatusSucceeded, StatusFailed, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Annotation describes changes to the metadata of a scan record. Nil
+// pointers leave the corresponding field untouched.
+type Annotation struct {
+	AddTags    []string
+	RemoveTags []string
+	Project    *string
+	Notes      *string
+	AppendNote string
+}
+
+// Empty reports whether the annotation changes nothing
+func (a Annotation) Empty() bool {
+	return len(a.AddTags) == 0 && len(a.RemoveTags) == 0 && a.Project == nil && a.Notes == nil && a.AppendNote == ""
+}
+
+// Apply changes record according to the annotation
+func (a Annotation) Apply(record *ScanRecord) {
+	tags := append(append([]string{}, record.Tags...), a.AddTags...)
+	removed := make(map[string]bool)
+	for _, tag := range normalizeTags(a.RemoveTags) {
+		removed[tag] = true
+	}
+
+	record.Tags = nil
+	for _, tag := range normalizeTags(tags) {
+		if !removed[tag] {
+			record.Tags = append(record.Tags, tag)
+		}
+	}
+
+	if a.Project != nil {
+		record.Project = strings.TrimSpace(*a.Project)
+	}
+	if a.Notes != nil {
+		record.Notes = strings.TrimSpace(*a.Notes)
+	}
+	if note := strings.TrimSpace(a.AppendNote); note != "" {
+		if record.Notes == "" {
+			record.Notes = note
+		} else {
+			record.Notes += "\n" + note
+		}
+	}
+}
+
+// HasTag reports whether the record carries tag
+func (r ScanRecord) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Duration returns how long the scan ran, or zero while it is still running
+func (r ScanRecord) Duration() time.Duration {
+	if r.CompletedAt.IsZero() {
+		return 0
+	}
+	return r.CompletedAt.Sub(r.StartTime)
+}
+
+// SeveritySummary renders the final issue counts, e.g. "H:1 M:0 L:3 I:7"
+func (r ScanRecord) SeveritySummary() string {
+	if r.SeverityCounts == nil {
+		return ""
+	}
+	return fmt.Sprintf("H:%d M:%d L:%d I:%d", r.SeverityCounts["high"], r.SeverityCounts["medium"], r.SeverityCounts["low"], r.SeverityCounts["info"])
+}
+
+// SplitTags splits a comma-separated tag list
+func SplitTags(list string) []string {
+	return normalizeTags(strings.Split(list, ","))
+}
+
+// normalizeTags lower-cases, trims, dedupes and sorts tags
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
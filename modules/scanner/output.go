@@ -0,0 +1,95 @@
+package scanner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Output formats of the scan listing
+const (
+	OutputTable = "table"
+	OutputWide  = "wide"
+	OutputJSON  = "json"
+	OutputCSV   = "csv"
+)
+
+// IsValidOutput reports whether format is a known listing format
+func IsValidOutput(format string) bool {
+	switch format {
+	case OutputTable, OutputWide, OutputJSON, OutputCSV:
+		return true
+	}
+	return false
+}
+
+// csvHeader lists the columns written by WriteScansCSV
+var csvHeader = []string{
+	"scan_id", "url", "status", "start_time", "completed_at", "instance",
+	"config_name", "scan_name", "project", "tags", "notes",
+	"high", "medium", "low", "info", "json_export", "html_export",
+}
+
+// WriteScansJSON writes records as an indented JSON array
+func WriteScansJSON(w io.Writer, records []ScanRecord) error {
+	if records == nil {
+		records = []ScanRecord{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// WriteScansCSV writes records as CSV with a header row. Tags are separated
+// by semicolons; the masked request and metrics are only part of the JSON output.
+func WriteScansCSV(w io.Writer, records []ScanRecord) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		counts := func(severity string) string {
+			if record.SeverityCounts == nil {
+				return ""
+			}
+			return strconv.Itoa(record.SeverityCounts[severity])
+		}
+		row := []string{
+			record.ScanID,
+			record.URL,
+			record.Status,
+			formatCSVTime(record.StartTime),
+			formatCSVTime(record.CompletedAt),
+			record.Instance,
+			record.ConfigName,
+			record.ScanName,
+			record.Project,
+			strings.Join(record.Tags, ";"),
+			record.Notes,
+			counts("high"),
+			counts("medium"),
+			counts("low"),
+			counts("info"),
+			record.JSONExport,
+			record.HTMLExport,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatCSVTime renders a time as RFC 3339, empty when unset
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,240 @@
+package scanner
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Columns accepted by ScanQuery.SortBy
+var sortColumns = map[string]func(a, b ScanRecord) int{
+	"id": func(a, b ScanRecord) int {
+		ai, aerr := strconv.Atoi(a.ScanID)
+		bi, berr := strconv.Atoi(b.ScanID)
+		if aerr == nil && berr == nil {
+			return ai - bi
+		}
+		return strings.Compare(a.ScanID, b.ScanID)
+	},
+	"url":       func(a, b ScanRecord) int { return strings.Compare(a.URL, b.URL) },
+	"status":    func(a, b ScanRecord) int { return strings.Compare(a.Status, b.Status) },
+	"started":   func(a, b ScanRecord) int { return a.StartTime.Compare(b.StartTime) },
+	"completed": func(a, b ScanRecord) int { return a.CompletedAt.Compare(b.CompletedAt) },
+	"instance":  func(a, b ScanRecord) int { return strings.Compare(a.InstanceID(), b.InstanceID()) },
+	"config":    func(a, b ScanRecord) int { return strings.Compare(a.ConfigName, b.ConfigName) },
+	"project":   func(a, b ScanRecord) int { return strings.Compare(a.Project, b.Project) },
+	"name":      func(a, b ScanRecord) int { return strings.Compare(a.ScanName, b.ScanName) },
+}
+
+// ScanQuery selects, orders and pages scan records. Zero values match everything.
+type ScanQuery struct {
+	Statuses []string
+	// Since and Until bound the start time to [Since, Until)
+	Since time.Time
+	Until time.Time
+	// URL is a glob matched against the full URL and its host
+	URL string
+	// Tags must all be present on a record
+	Tags    []string
+	Project string
+	// Config is a glob matched against the configuration name
+	Config string
+	// Instance is a glob matched against the owning instance address or ID.
+	// It widens the listing beyond the tracker's instance; "all" matches every
+	// record and "unclaimed" matches records of older versions.
+	Instance string
+
+	SortBy     string
+	Descending bool
+	// Page is 1-based; Limit 0 disables pagination
+	Limit int
+	Page  int
+}
+
+// ParseSort parses "column" or "column:asc|desc"
+func ParseSort(value string) (column string, descending bool, err error) {
+	column, order, _ := strings.Cut(strings.ToLower(strings.TrimSpace(value)), ":")
+	if column == "" {
+		return "", false, nil
+	}
+	if column == "start" || column == "date" || column == "time" {
+		column = "started"
+	}
+	if _, ok := sortColumns[column]; !ok {
+		return "", false, fmt.Errorf("unknown sort column %q (use id, url, status, started, completed, instance, config, project or name)", column)
+	}
+	switch order {
+	case "", "asc":
+	case "desc":
+		descending = true
+	default:
+		return "", false, fmt.Errorf("unknown sort order %q (use asc or desc)", order)
+	}
+	return column, descending, nil
+}
+
+// ParseTimeBound parses an absolute date ("2006-01-02", "2006-01-02 15:04",
+// RFC 3339) or a relative age such as "36h", "7d" or "2w" before now
+func ParseTimeBound(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if n := len(value); n > 1 {
+		if amount, err := strconv.Atoi(value[:n-1]); err == nil && amount >= 0 {
+			switch value[n-1] {
+			case 'd':
+				return now.AddDate(0, 0, -amount), nil
+			case 'w':
+				return now.AddDate(0, 0, -7*amount), nil
+			}
+		}
+	}
+	if age, err := time.ParseDuration(value); err == nil {
+		return now.Add(-age), nil
+	}
+
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD, RFC 3339 or an age like 7d)", value)
+}
+
+// Filtered reports whether the query restricts the records in any way
+func (q ScanQuery) Filtered() bool {
+	return len(q.Statuses) > 0 || !q.Since.IsZero() || !q.Until.IsZero() || q.URL != "" ||
+		len(q.Tags) > 0 || q.Project != "" || q.Config != "" || q.Instance != ""
+}
+
+// Match reports whether a record passes every filter of the query
+func (q ScanQuery) Match(record ScanRecord) bool {
+	if len(q.Statuses) > 0 && !containsFold(q.Statuses, record.Status) {
+		return false
+	}
+	if !q.Since.IsZero() && record.StartTime.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !record.StartTime.Before(q.Until) {
+		return false
+	}
+	if q.URL != "" && !matchURL(q.URL, record.URL) {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !record.HasTag(tag) {
+			return false
+		}
+	}
+	if q.Project != "" && !globMatch(q.Project, record.Project) {
+		return false
+	}
+	if q.Config != "" && !globMatch(q.Config, record.ConfigName) {
+		return false
+	}
+	if q.Instance != "" && !q.matchInstance(record) {
+		return false
+	}
+	return true
+}
+
+// Apply filters and sorts records and returns the requested page together
+// with the number of matching records
+func (q ScanQuery) Apply(records []ScanRecord) ([]ScanRecord, int) {
+	var matched []ScanRecord
+	for _, record := range records {
+		if q.Match(record) {
+			matched = append(matched, record)
+		}
+	}
+
+	compare := sortColumns["started"]
+	if q.SortBy != "" && sortColumns[q.SortBy] != nil {
+		compare = sortColumns[q.SortBy]
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if q.Descending {
+			return compare(matched[j], matched[i]) < 0
+		}
+		return compare(matched[i], matched[j]) < 0
+	})
+
+	total := len(matched)
+	if q.Limit <= 0 {
+		return matched, total
+	}
+	page := q.Page
+	if page < 1 {
+		page = 1
+	}
+	start := (page - 1) * q.Limit
+	if start >= total {
+		return nil, total
+	}
+	end := start + q.Limit
+	if end > total {
+		end = total
+	}
+	return matched[start:end], total
+}
+
+// matchInstance matches the owning instance by address or ID
+func (q ScanQuery) matchInstance(record ScanRecord) bool {
+	switch strings.ToLower(q.Instance) {
+	case "all":
+		return true
+	case "unclaimed":
+		return record.Instance == ""
+	}
+	return globMatch(q.Instance, record.Instance) || globMatch(q.Instance, record.InstanceID())
+}
+
+// matchURL matches a glob against the full URL or its host
+func matchURL(pattern, rawURL string) bool {
+	if globMatch(pattern, rawURL) {
+		return true
+	}
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Hostname() != "" {
+		return globMatch(pattern, parsed.Hostname())
+	}
+	return false
+}
+
+// globMatch matches a case-insensitive glob where * spans any characters,
+// including slashes, and ? matches exactly one
+func globMatch(pattern, value string) bool {
+	var expr strings.Builder
+	expr.WriteString("(?i)^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	matched, err := regexp.MatchString(expr.String(), value)
+	return err == nil && matched
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
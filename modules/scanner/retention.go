@@ -0,0 +1,354 @@
+package scanner
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Retention actions
+const (
+	RetentionArchive = "archive"
+	RetentionDelete  = "delete"
+)
+
+// retentionKey stores the retention policy in the storage meta data
+const retentionKey = "retention_policy"
+
+// archiveBundleName is the bundle entry of a retention archive
+const archiveBundleName = "bundle.json"
+
+// RetentionPolicy decides which scans expire. A scan is kept when any rule
+// keeps it; unfinished scans never expire and a policy without rules keeps
+// everything.
+type RetentionPolicy struct {
+	// KeepLast keeps the newest N scans of every target URL
+	KeepLast int `json:"keep_last,omitempty"`
+	// KeepDays keeps scans started in the last M days
+	KeepDays int `json:"keep_days,omitempty"`
+	// KeepHighSeverity keeps scans that found high-severity issues, and scans
+	// whose issues were never counted (synced, imported or unmonitored scans)
+	KeepHighSeverity bool `json:"keep_high_severity"`
+	// Action is RetentionArchive or RetentionDelete
+	Action string `json:"action,omitempty"`
+	// ArchiveDir receives the archives; ~/.burp-cli/archive when empty
+	ArchiveDir string `json:"archive_dir,omitempty"`
+}
+
+// DefaultRetentionPolicy keeps everything and archives when rules are added
+func DefaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{KeepHighSeverity: true, Action: RetentionArchive}
+}
+
+// Validate checks the action and rule values
+func (p RetentionPolicy) Validate() error {
+	if p.KeepLast < 0 || p.KeepDays < 0 {
+		return fmt.Errorf("keep-last and keep-days must not be negative")
+	}
+	switch p.Action {
+	case "", RetentionArchive, RetentionDelete:
+	default:
+		return fmt.Errorf("unknown retention action %q (use archive or delete)", p.Action)
+	}
+	return nil
+}
+
+// HasRules reports whether the policy can expire anything
+func (p RetentionPolicy) HasRules() bool {
+	return p.KeepLast > 0 || p.KeepDays > 0
+}
+
+// String describes the policy in one line
+func (p RetentionPolicy) String() string {
+	var rules []string
+	if p.KeepLast > 0 {
+		rules = append(rules, fmt.Sprintf("keep last %d per target", p.KeepLast))
+	}
+	if p.KeepDays > 0 {
+		rules = append(rules, fmt.Sprintf("keep %d days", p.KeepDays))
+	}
+	if len(rules) == 0 {
+		return "keep everything"
+	}
+	if p.KeepHighSeverity {
+		rules = append(rules, "always keep high-severity and uncounted scans")
+	}
+	action := p.Action
+	if action == "" {
+		action = RetentionArchive
+	}
+	return strings.Join(rules, ", ") + "; " + action + " the rest"
+}
+
+// Expired returns the records the policy does not keep, oldest first
+func (p RetentionPolicy) Expired(records []ScanRecord, now time.Time) []ScanRecord {
+	if !p.HasRules() {
+		return nil
+	}
+
+	// Rank the scans of each target from newest to oldest
+	sorted := append([]ScanRecord{}, records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartTime.After(sorted[j].StartTime)
+	})
+	rank := make(map[string]int)
+	seen := make(map[string]int)
+	for _, record := range sorted {
+		seen[record.URL]++
+		rank[record.Key()] = seen[record.URL]
+	}
+
+	var expired []ScanRecord
+	cutoff := now.AddDate(0, 0, -p.KeepDays)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		record := sorted[i]
+		switch {
+		case !IsFinalStatus(record.Status):
+		case p.KeepLast > 0 && rank[record.Key()] <= p.KeepLast:
+		case p.KeepDays > 0 && !record.StartTime.Before(cutoff):
+		case p.KeepHighSeverity && (record.SeverityCounts == nil || record.SeverityCounts["high"] > 0):
+		default:
+			expired = append(expired, record)
+		}
+	}
+	return expired
+}
+
+// ExportFiles returns the export files recorded for a scan
+func (r ScanRecord) ExportFiles() []string {
+	var files []string
+	for _, file := range append([]string{r.JSONExport, r.HTMLExport}, r.Exports...) {
+		if file != "" && !containsString(files, file) {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// AddExports records further export files of a scan
+func (r *ScanRecord) AddExports(files ...string) {
+	for _, file := range files {
+		if file != "" && !containsString(r.ExportFiles(), file) {
+			r.Exports = append(r.Exports, file)
+		}
+	}
+}
+
+// PruneResult describes what Prune removed, or would remove in a dry run
+type PruneResult struct {
+	Records int
+	// Files are the existing export files of the expired scans
+	Files []string
+	Bytes int64
+	// Archive is the path of the written archive
+	Archive string
+}
+
+// RetentionPolicy returns the stored retention policy
+func (st *ScanTracker) RetentionPolicy() (RetentionPolicy, error) {
+	policy := DefaultRetentionPolicy()
+	value, err := st.storage.GetMeta(retentionKey)
+	if err != nil || value == "" {
+		return policy, err
+	}
+	if err := json.Unmarshal([]byte(value), &policy); err != nil {
+		return policy, fmt.Errorf("failed to parse retention policy: %v", err)
+	}
+	return policy, nil
+}
+
+// SetRetentionPolicy stores the retention policy
+func (st *ScanTracker) SetRetentionPolicy(policy RetentionPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	return st.storage.SetMeta(retentionKey, string(data))
+}
+
+// Prune archives or deletes expired records together with their export
+// files. Archives are gzip-compressed tarballs holding a scan bundle and the
+// export files; "scans import" restores the records. Nothing is changed when
+// dryRun is set.
+func (st *ScanTracker) Prune(expired []ScanRecord, policy RetentionPolicy, dryRun bool) (PruneResult, error) {
+	result := PruneResult{Records: len(expired)}
+	for _, record := range expired {
+		for _, file := range record.ExportFiles() {
+			if info, err := os.Stat(file); err == nil && info.Mode().IsRegular() {
+				result.Files = append(result.Files, file)
+				result.Bytes += info.Size()
+			}
+		}
+	}
+	if dryRun || len(expired) == 0 {
+		return result, nil
+	}
+
+	if policy.Action != RetentionDelete {
+		archive, err := st.writeArchive(expired, policy.ArchiveDir)
+		if err != nil {
+			return result, err
+		}
+		result.Archive = archive
+	}
+
+	for _, record := range expired {
+		if err := st.storage.Remove(record.Key()); err != nil {
+			return result, err
+		}
+	}
+	for _, file := range result.Files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return result, fmt.Errorf("failed to delete %s: %v", file, err)
+		}
+	}
+	return result, nil
+}
+
+// writeArchive writes expired records and their export files to a new archive
+func (st *ScanTracker) writeArchive(records []ScanRecord, dir string) (string, error) {
+	if dir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %v", err)
+		}
+		dir = filepath.Join(homeDir, ".burp-cli", "archive")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %v", err)
+	}
+
+	bundle, err := st.NewBundle(records, true)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal archived scans: %v", err)
+	}
+
+	// The random suffix keeps prunes started in the same second apart
+	file, err := os.CreateTemp(dir, fmt.Sprintf("burp-cli-archive_%s_*.tar.gz", time.Now().Format("20060102_150405")))
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive: %v", err)
+	}
+	path := file.Name()
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", fmt.Errorf("failed to create archive: %v", err)
+	}
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	err = writeTarFile(tw, archiveBundleName, data, time.Now())
+	for _, record := range records {
+		if err != nil {
+			break
+		}
+		folder := "exports/" + archiveFolder(record)
+		for _, export := range record.ExportFiles() {
+			// Exports that are already gone have nothing to archive. Any other
+			// failure aborts the prune, so no record loses its exports.
+			info, statErr := os.Stat(export)
+			if os.IsNotExist(statErr) {
+				continue
+			}
+			if statErr != nil {
+				err = statErr
+				break
+			}
+			if !info.Mode().IsRegular() {
+				continue
+			}
+			var content []byte
+			if content, err = os.ReadFile(export); err != nil {
+				break
+			}
+			if err = writeTarFile(tw, folder+"/"+filepath.Base(export), content, info.ModTime()); err != nil {
+				break
+			}
+		}
+	}
+	for _, closer := range []io.Closer{tw, gz, file} {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("failed to write archive: %v", err)
+	}
+	return path, nil
+}
+
+// writeTarFile adds one regular file to a tar stream
+func writeTarFile(tw *tar.Writer, name string, content []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
+// readArchiveBundle extracts the bundle of a retention archive
+func readArchiveBundle(r io.Reader) ([]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("archive has no %s", archiveBundleName)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Name == archiveBundleName {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// archiveFolder names the folder of a scan's exports inside an archive
+func archiveFolder(record ScanRecord) string {
+	replacer := strings.NewReplacer(":", "_", "#", "_", "/", "_", "\\", "_")
+	instance := record.InstanceID()
+	if instance == "" {
+		return "scan_" + replacer.Replace(record.ScanID)
+	}
+	if record.Session > 0 {
+		instance = fmt.Sprintf("%s_session_%d", instance, record.Session)
+	}
+	return replacer.Replace(instance) + "_scan_" + replacer.Replace(record.ScanID)
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
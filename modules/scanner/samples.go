@@ -0,0 +1,186 @@
+package scanner
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSampleInterval is the minimum time between two metric samples of a
+// monitored scan; status changes are always sampled
+const DefaultSampleInterval = 30 * time.Second
+
+// SampleMetrics are the scan_metrics fields kept in metric samples, in display order
+var SampleMetrics = []string{
+	"crawl_requests_made",
+	"crawl_requests_queued",
+	"crawl_network_errors",
+	"audit_queue_items_completed",
+	"audit_queue_items_waiting",
+	"audit_requests_made",
+	"audit_network_errors",
+	"issue_events",
+	"crawl_and_audit_progress",
+}
+
+// sparkBlocks render sparkline levels from low to high
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// MetricSample is one reading of a scan's metrics
+type MetricSample struct {
+	Time   time.Time          `json:"time"`
+	Status string             `json:"status"`
+	Values map[string]float64 `json:"values"`
+}
+
+// NewMetricSample keeps the numeric SampleMetrics of a scan_metrics object
+func NewMetricSample(status string, metrics map[string]interface{}) MetricSample {
+	sample := MetricSample{
+		Time:   time.Now(),
+		Status: status,
+		Values: make(map[string]float64),
+	}
+	for _, name := range SampleMetrics {
+		switch value := metrics[name].(type) {
+		case float64:
+			sample.Values[name] = value
+		case int:
+			sample.Values[name] = float64(value)
+		case string:
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				sample.Values[name] = parsed
+			}
+		}
+	}
+	return sample
+}
+
+// SnapshotFunc fetches the current status and scan_metrics of a scan
+type SnapshotFunc func(scanID string) (status string, metrics map[string]interface{}, err error)
+
+// RecordSample stores a metric sample for a scan of the instance
+func (st *ScanTracker) RecordSample(scanID string, sample MetricSample) error {
+	record := st.GetScanByID(scanID)
+	if record == nil {
+		return fmt.Errorf("scan ID %s not found", scanID)
+	}
+	return st.storage.AddSamples(record.Key(), sample)
+}
+
+// GetSamples returns the metric samples of a scan in chronological order
+func (st *ScanTracker) GetSamples(scanID string) ([]MetricSample, error) {
+	record := st.GetScanByID(scanID)
+	if record == nil {
+		return nil, fmt.Errorf("scan ID %s not found", scanID)
+	}
+	return st.storage.ListSamples(record.Key())
+}
+
+// SampleRunningScans records a sample for every unfinished scan of the
+// instance's current session and applies status changes. Returns the number
+// of samples taken.
+func (st *ScanTracker) SampleRunningScans(fetch SnapshotFunc) (int, error) {
+	session, err := st.Session()
+	if err != nil {
+		return 0, err
+	}
+
+	sampled := 0
+	for _, record := range st.GetAllScans() {
+		if IsFinalStatus(record.Status) || !st.Current(record, session) {
+			continue
+		}
+
+		status, metrics, err := fetch(record.ScanID)
+		if err != nil {
+			// The scan may have been deleted from Burp; try the others
+			continue
+		}
+		if err := st.RecordSample(record.ScanID, NewMetricSample(status, metrics)); err != nil {
+			return sampled, err
+		}
+		sampled++
+
+		if status != "" && status != record.Status {
+			if err := st.UpdateScanStatus(record.ScanID, status); err != nil {
+				return sampled, err
+			}
+		}
+	}
+	return sampled, nil
+}
+
+// WriteSamplesCSV writes samples as CSV with one column per metric
+func WriteSamplesCSV(w io.Writer, samples []MetricSample) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{"time", "status"}, SampleMetrics...)); err != nil {
+		return err
+	}
+	for _, sample := range samples {
+		row := []string{sample.Time.Format(time.RFC3339), sample.Status}
+		for _, name := range SampleMetrics {
+			value, ok := sample.Values[name]
+			if !ok {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Series returns the values of one metric and whether any sample carries it
+func Series(samples []MetricSample, name string) ([]float64, bool) {
+	values := make([]float64, 0, len(samples))
+	found := false
+	last := 0.0
+	for _, sample := range samples {
+		if value, ok := sample.Values[name]; ok {
+			last = value
+			found = true
+		}
+		values = append(values, last)
+	}
+	return values, found
+}
+
+// Sparkline renders values in at most width characters. Longer series are
+// bucketed, keeping the last value of each bucket.
+func Sparkline(values []float64, width int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	if width > 0 && len(values) > width {
+		bucketed := make([]float64, width)
+		for i := range bucketed {
+			end := (i + 1) * len(values) / width
+			bucketed[i] = values[end-1]
+		}
+		values = bucketed
+	}
+
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, value := range values {
+		low = math.Min(low, value)
+		high = math.Max(high, value)
+	}
+
+	var line strings.Builder
+	for _, value := range values {
+		level := 0
+		if high > low {
+			level = int((value - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		line.WriteRune(sparkBlocks[level])
+	}
+	return line.String()
+}
@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// sessionKey prefixes the per-instance scan ID session in the storage meta data
+const sessionKey = "session/"
+
+// Session numbers the scan ID sequences of a Burp instance. Burp starts its
+// scan IDs over after a restart or a project change, so a scan ID is only
+// unique within one session. Records of earlier sessions keep their history
+// but are never updated again.
+type Session struct {
+	// Number is 0 until the first reset was detected
+	Number int `json:"number"`
+	// Since is when the reset was detected; zero for the first session
+	Since time.Time `json:"since,omitzero"`
+}
+
+// Session returns the current scan ID session of the instance
+func (st *ScanTracker) Session() (Session, error) {
+	var session Session
+	value, err := st.storage.GetMeta(sessionKey + st.instance.ID())
+	if err != nil || value == "" {
+		return session, err
+	}
+	if err := json.Unmarshal([]byte(value), &session); err != nil {
+		return session, fmt.Errorf("failed to parse scan session of %s: %v", st.instance, err)
+	}
+	return session, nil
+}
+
+// BeginSession records that Burp started its scan IDs over at the given time.
+// Scans added afterwards get new keys, and tracked scans started before the
+// reset are no longer found by their scan ID.
+func (st *ScanTracker) BeginSession(at time.Time) (Session, error) {
+	session, err := st.Session()
+	if err != nil {
+		return session, err
+	}
+	session = Session{Number: session.Number + 1, Since: at}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return session, err
+	}
+	return session, st.storage.SetMeta(sessionKey+st.instance.ID(), string(data))
+}
+
+// Current reports whether a record can still be updated by its scan ID: it
+// belongs to the instance's current session, or is an unclaimed legacy record
+// started after the last reset
+func (st *ScanTracker) Current(record ScanRecord, session Session) bool {
+	if record.StartTime.Before(session.Since) {
+		return false
+	}
+	if record.Instance == "" {
+		return true
+	}
+	return st.instance.Owns(record) && record.Session == session.Number
+}
@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestScanIDReuseAfterReset starts scan 1, lets Burp start its IDs over and
+// starts scan 1 again. Both scans must be kept, and updates by scan ID must
+// only reach the scan of the current session.
+func TestScanIDReuseAfterReset(t *testing.T) {
+	resets := map[string]func(t *testing.T, tracker *ScanTracker){
+		// trackScan adds a scan ID that is already tracked
+		"detected on add": func(t *testing.T, tracker *ScanTracker) {},
+		// sync finds the watermark scan missing
+		"detected by sync": func(t *testing.T, tracker *ScanTracker) {
+			if _, err := tracker.BeginSession(time.Now()); err != nil {
+				t.Fatalf("failed to begin session: %v", err)
+			}
+			if record := tracker.GetScanByID("1"); record != nil {
+				t.Errorf("scan 1 of the previous session is still found by its ID")
+			}
+			if err := tracker.UpdateScanStatus("1", StatusFailed); err == nil {
+				t.Errorf("updated scan 1 of the previous session")
+			}
+		},
+	}
+
+	for _, backend := range []string{BackendJSON, BackendBolt} {
+		for name, reset := range resets {
+			t.Run(backend+"/"+name, func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "scan_history")
+				tracker := NewScanTrackerWithStorage(openTestStorage(t, backend, path), NewInstance("127.0.0.1", "1337", ""))
+
+				if err := tracker.AddScan("1", "https://old.example.com", "", ""); err != nil {
+					t.Fatalf("failed to add first scan: %v", err)
+				}
+				for _, status := range []string{StatusCrawling, StatusAuditing, StatusSucceeded} {
+					if err := tracker.UpdateScanStatus("1", status); err != nil {
+						t.Fatalf("failed to update first scan: %v", err)
+					}
+				}
+
+				reset(t, tracker)
+
+				if err := tracker.AddScan("1", "https://new.example.com", "", ""); err != nil {
+					t.Fatalf("failed to add scan with reused ID: %v", err)
+				}
+				if err := tracker.UpdateScanStatus("1", StatusCrawling); err != nil {
+					t.Fatalf("failed to update scan with reused ID: %v", err)
+				}
+				if err := tracker.RecordSample("1", NewMetricSample(StatusCrawling, nil)); err != nil {
+					t.Fatalf("failed to record sample: %v", err)
+				}
+
+				records := tracker.GetAllScans()
+				if len(records) != 2 {
+					t.Fatalf("got %d records, want 2", len(records))
+				}
+				old, current := records[0], records[1]
+				if old.Key() == current.Key() {
+					t.Fatalf("both scans are stored as %s", old.Key())
+				}
+				if old.URL != "https://old.example.com" || old.Status != StatusSucceeded {
+					t.Errorf("first scan changed: %s %s", old.URL, old.Status)
+				}
+				if current.URL != "https://new.example.com" || current.Status != StatusCrawling {
+					t.Errorf("second scan is %s %s, want https://new.example.com %s", current.URL, current.Status, StatusCrawling)
+				}
+				if current.Session != old.Session+1 {
+					t.Errorf("second scan has session %d, want %d", current.Session, old.Session+1)
+				}
+
+				if samples, _ := tracker.storage.ListSamples(old.Key()); len(samples) != 0 {
+					t.Errorf("first scan got %d samples of the second", len(samples))
+				}
+				if samples, _ := tracker.storage.ListSamples(current.Key()); len(samples) != 1 {
+					t.Errorf("second scan has %d samples, want 1", len(samples))
+				}
+			})
+		}
+	}
+}
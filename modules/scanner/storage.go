@@ -0,0 +1,87 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Storage backend names accepted by BURP_CLI_SCAN_STORE
+const (
+	BackendBolt = "bolt"
+	BackendJSON = "json"
+)
+
+// Storage defines the interface for scan history persistence. Records are
+// addressed by ScanRecord.Key, which includes the owning Burp instance.
+type Storage interface {
+	// Add stores a new record; its key must not exist yet
+	Add(record ScanRecord) error
+
+	// Update applies fn to a stored record atomically. The record is re-keyed
+	// if fn changes its instance.
+	Update(key string, fn func(record *ScanRecord) error) error
+
+	// Get returns a record, or nil if the key is unknown
+	Get(key string) (*ScanRecord, error)
+
+	// Remove deletes a record
+	Remove(key string) error
+
+	// List returns every record ordered by start time
+	List() ([]ScanRecord, error)
+
+	// ListByInstance returns records of an instance ID ("" for legacy records)
+	ListByInstance(instanceID string) ([]ScanRecord, error)
+
+	// ListByStatus returns records with the given status
+	ListByStatus(status string) ([]ScanRecord, error)
+
+	// ListByURL returns records for the given target URL
+	ListByURL(url string) ([]ScanRecord, error)
+
+	// ListBetween returns records started in [from, to)
+	ListBetween(from, to time.Time) ([]ScanRecord, error)
+
+	// GetMeta returns a stored setting, or "" if it is unset
+	GetMeta(key string) (string, error)
+
+	// SetMeta stores a setting such as the sync watermark of an instance
+	SetMeta(key, value string) error
+
+	// AddSamples stores metric samples of a record; a sample with the same
+	// time as a stored one replaces it
+	AddSamples(key string, samples ...MetricSample) error
+
+	// ListSamples returns the metric samples of a record in chronological order
+	ListSamples(key string) ([]MetricSample, error)
+}
+
+// NewStorage opens the scan history backend in configDir. The embedded
+// database is the default; BURP_CLI_SCAN_STORE=json keeps the plain JSON file.
+// Records of an existing scan_history.json are migrated into the database once.
+func NewStorage(configDir string) (Storage, error) {
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %v", err)
+	}
+
+	jsonPath := filepath.Join(configDir, "scan_history.json")
+
+	switch backend := strings.ToLower(os.Getenv("BURP_CLI_SCAN_STORE")); backend {
+	case "", BackendBolt:
+		storage, err := NewBoltStorage(filepath.Join(configDir, "scan_history.db"))
+		if err != nil {
+			return nil, err
+		}
+		if err := storage.MigrateJSON(jsonPath); err != nil {
+			return nil, err
+		}
+		return storage, nil
+	case BackendJSON:
+		return NewJSONStorage(jsonPath)
+	default:
+		return nil, fmt.Errorf("unknown scan history backend %q (use %q or %q)", backend, BackendBolt, BackendJSON)
+	}
+}
@@ -0,0 +1,153 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+const (
+	testWriters        = 8
+	testScansPerWriter = 10
+
+	// Environment of the writer process started by TestConcurrentHistoryWrites
+	envTestBackend = "BURP_CLI_TEST_BACKEND"
+	envTestHistory = "BURP_CLI_TEST_HISTORY"
+)
+
+// TestConcurrentHistoryWrites adds and updates scans from several goroutines
+// and a second process sharing one history, so both the in-process mutex and
+// the file lock are exercised. No record may be lost and the file must parse.
+func TestConcurrentHistoryWrites(t *testing.T) {
+	for _, backend := range []string{BackendJSON, BackendBolt} {
+		t.Run(backend, func(t *testing.T) {
+			name := "scan_history.json"
+			if backend == BackendBolt {
+				name = "scan_history.db"
+			}
+			path := filepath.Join(t.TempDir(), name)
+			storage := openTestStorage(t, backend, path)
+
+			var output bytes.Buffer
+			child := exec.Command(os.Args[0], "-test.run=^TestHistoryWriterProcess$")
+			child.Env = append(os.Environ(), envTestBackend+"="+backend, envTestHistory+"="+path)
+			child.Stdout = &output
+			child.Stderr = &output
+			if err := child.Start(); err != nil {
+				t.Fatalf("failed to start writer process: %v", err)
+			}
+
+			for _, err := range writeScans(storage, NewInstance("127.0.0.1", "1337", "")) {
+				t.Error(err)
+			}
+			if err := child.Wait(); err != nil {
+				t.Fatalf("writer process failed: %v\n%s", err, output.String())
+			}
+
+			// Reopen so the check reads what is on disk
+			records, err := openTestStorage(t, backend, path).List()
+			if err != nil {
+				t.Fatalf("failed to list scans: %v", err)
+			}
+
+			want := 2 * testWriters * testScansPerWriter
+			if len(records) != want {
+				t.Fatalf("got %d records, want %d", len(records), want)
+			}
+			seen := make(map[string]bool)
+			for _, record := range records {
+				if seen[record.Key()] {
+					t.Errorf("scan %s stored twice", record.Key())
+				}
+				seen[record.Key()] = true
+				if record.Status != "succeeded" {
+					t.Errorf("scan %s has status %q, want %q", record.Key(), record.Status, "succeeded")
+				}
+			}
+
+			if backend == BackendJSON {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("failed to read history: %v", err)
+				}
+				var history jsonHistory
+				if err := json.Unmarshal(data, &history); err != nil {
+					t.Fatalf("history file does not parse: %v", err)
+				}
+				if len(history.Records) != want {
+					t.Errorf("history file has %d records, want %d", len(history.Records), want)
+				}
+			}
+		})
+	}
+}
+
+// TestHistoryWriterProcess is the second writer of TestConcurrentHistoryWrites.
+// It only runs as a child process.
+func TestHistoryWriterProcess(t *testing.T) {
+	path := os.Getenv(envTestHistory)
+	if path == "" {
+		t.Skip("started by TestConcurrentHistoryWrites")
+	}
+
+	storage := openTestStorage(t, os.Getenv(envTestBackend), path)
+	for _, err := range writeScans(storage, NewInstance("127.0.0.1", "1338", "")) {
+		t.Error(err)
+	}
+}
+
+// openTestStorage opens the JSON or bolt backend at path
+func openTestStorage(t *testing.T, backend, path string) Storage {
+	t.Helper()
+
+	var storage Storage
+	var err error
+	if backend == BackendBolt {
+		storage, err = NewBoltStorage(path)
+	} else {
+		storage, err = NewJSONStorage(path)
+	}
+	if err != nil {
+		t.Fatalf("failed to open %s storage: %v", backend, err)
+	}
+	return storage
+}
+
+// writeScans adds testScansPerWriter scans per goroutine and moves each through
+// the scan phases. It returns the errors of all goroutines.
+func writeScans(storage Storage, instance Instance) []error {
+	tracker := NewScanTrackerWithStorage(storage, instance)
+
+	var mutex sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for writer := 0; writer < testWriters; writer++ {
+		wg.Add(1)
+		go func(writer int) {
+			defer wg.Done()
+			for i := 0; i < testScansPerWriter; i++ {
+				scanID := strconv.Itoa(writer*testScansPerWriter + i + 1)
+				err := tracker.AddScan(scanID, fmt.Sprintf("https://%d.example.com", writer), "Crawl and Audit - Fast", "")
+				for _, status := range []string{"crawling", "auditing", "succeeded"} {
+					if err != nil {
+						break
+					}
+					err = tracker.UpdateScanStatus(scanID, status)
+				}
+				if err != nil {
+					mutex.Lock()
+					errs = append(errs, fmt.Errorf("%s scan %s: %v", instance, scanID, err))
+					mutex.Unlock()
+				}
+			}
+		}(writer)
+	}
+	wg.Wait()
+	return errs
+}
@@ -1,10 +1,12 @@
 package scanner
 
 import (
-	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"time"
 )
 
@@ -17,136 +19,326 @@ type ScanRecord struct {
 	ConfigName  string    `json:"config_name,omitempty"`
 	ScanName    string    `json:"scan_name,omitempty"`
 	LastChecked time.Time `json:"last_checked,omitempty"`
+	Instance    string    `json:"instance,omitempty"`
+	KeyHint     string    `json:"key_hint,omitempty"`
+
+	// Session is the scan ID session of the instance the scan ID belongs to
+	Session int `json:"session,omitempty"`
+	// Origin is the tester a local instance of an imported scan belongs to
+	Origin string `json:"origin,omitempty"`
+
+	// Metadata given when the scan was started or added with "scans annotate"
+	Tags    []string `json:"tags,omitempty"`
+	Project string   `json:"project,omitempty"`
+	Notes   string   `json:"notes,omitempty"`
+
+	// Request is the effective scan request sent to Burp, with secrets masked
+	Request map[string]interface{} `json:"request,omitempty"`
+
+	// Phases lists every status change in order
+	Phases []PhaseChange `json:"phases,omitempty"`
+
+	// Final snapshot recorded when the scan completes
+	CompletedAt    time.Time              `json:"completed_at,omitzero"`
+	Metrics        map[string]interface{} `json:"metrics,omitempty"`
+	SeverityCounts map[string]int         `json:"severity_counts,omitempty"`
+	JSONExport     string                 `json:"json_export,omitempty"`
+	HTMLExport     string                 `json:"html_export,omitempty"`
+
+	// Exports are further report files, e.g. written by -LA
+	Exports []string `json:"exports,omitempty"`
+}
+
+// Owner returns the Burp instance the scan was started on
+func (r ScanRecord) Owner() Instance {
+	owner := Instance{KeyHint: r.KeyHint, Origin: r.Origin}
+	if r.Instance != "" {
+		owner.Host, owner.Port, _ = net.SplitHostPort(r.Instance)
+	}
+	return owner
+}
+
+// InstanceID returns the owning instance ID, empty for records created
+// before instances were tracked
+func (r ScanRecord) InstanceID() string {
+	if r.Instance == "" {
+		return ""
+	}
+	return r.Owner().ID()
+}
+
+// Key returns the storage key. Records without an instance keep their bare
+// scan ID so histories written by older versions stay addressable. Scan IDs
+// Burp reused after a reset are qualified with their session.
+func (r ScanRecord) Key() string {
+	if r.Instance == "" {
+		return r.ScanID
+	}
+	if r.Session > 0 {
+		return fmt.Sprintf("%s@%d/%s", r.InstanceID(), r.Session, r.ScanID)
+	}
+	return r.InstanceID() + "/" + r.ScanID
 }
 
-// ScanTracker manages scan records
+// watermarkKey prefixes the per-instance sync watermark in the storage meta data
+const watermarkKey = "sync_watermark/"
+
+// ScanTracker manages the scan records of one Burp instance on top of a
+// Storage backend. Records from older versions carry no instance; they are
+// visible to every instance and claimed by the first one that updates them.
 type ScanTracker struct {
-	Records []ScanRecord `json:"records"`
-	filePath string
+	storage  Storage
+	instance Instance
 }
 
-// NewScanTracker creates a new scan tracker
-func NewScanTracker() (*ScanTracker, error) {
+// NewScanTracker creates a scan tracker for instance using the history in ~/.burp-cli
+func NewScanTracker(instance Instance) (*ScanTracker, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user home directory: %v", err)
 	}
-	
-	configDir := filepath.Join(homeDir, ".burp-cli")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create config directory: %v", err)
-	}
-	
-	filePath := filepath.Join(configDir, "scan_history.json")
-	
-	tracker := &ScanTracker{
-		Records: []ScanRecord{},
-		filePath: filePath,
-	}
-	
-	// Load existing records if file exists
-	if _, err := os.Stat(filePath); err == nil {
-		if err := tracker.load(); err != nil {
-			return nil, err
-		}
+
+	storage, err := NewStorage(filepath.Join(homeDir, ".burp-cli"))
+	if err != nil {
+		return nil, err
 	}
-	
-	return tracker, nil
+
+	return NewScanTrackerWithStorage(storage, instance), nil
+}
+
+// NewScanTrackerWithStorage creates a scan tracker for instance on the given backend
+func NewScanTrackerWithStorage(storage Storage, instance Instance) *ScanTracker {
+	return &ScanTracker{storage: storage, instance: instance}
+}
+
+// Instance returns the Burp instance the tracker is scoped to
+func (st *ScanTracker) Instance() Instance {
+	return st.instance
 }
 
 // AddScan adds a new scan record
 func (st *ScanTracker) AddScan(scanID, url, configName, scanName string) error {
-	record := ScanRecord{
+	return st.AddScanRecord(ScanRecord{
 		ScanID:     scanID,
 		URL:        url,
-		StartTime:  time.Now(),
-		Status:     "running",
 		ConfigName: configName,
 		ScanName:   scanName,
-		LastChecked: time.Now(),
+	})
+}
+
+// AddScanRecord adds a new scan record with its metadata. The instance and
+// session are always the tracker's; start time, status and check time default
+// to now and "running". A scan ID that is already tracked in the current
+// session means Burp started its IDs over, so a new session begins.
+func (st *ScanTracker) AddScanRecord(record ScanRecord) error {
+	session, err := st.Session()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if record.StartTime.IsZero() {
+		record.StartTime = now
+	}
+	if record.Status == "" {
+		record.Status = StatusRunning
+	}
+	if record.LastChecked.IsZero() {
+		record.LastChecked = now
+	}
+	if len(record.Phases) == 0 {
+		record.Phases = []PhaseChange{{Status: record.Status, At: record.StartTime, Unexpected: !IsKnownStatus(record.Status)}}
 	}
-	
-	st.Records = append(st.Records, record)
-	return st.save()
+	record.Tags = normalizeTags(record.Tags)
+	record.Instance = st.instance.Address()
+	record.KeyHint = st.instance.KeyHint
+	record.Session = session.Number
+
+	existing, err := st.storage.Get(record.Key())
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if session, err = st.BeginSession(record.StartTime); err != nil {
+			return err
+		}
+		record.Session = session.Number
+	}
+
+	return st.storage.Add(record)
 }
 
-// UpdateScanStatus updates the status of a scan
+// UpdateScanStatus updates the status of a scan; see TransitionScan
 func (st *ScanTracker) UpdateScanStatus(scanID, status string) error {
-	for i := range st.Records {
-		if st.Records[i].ScanID == scanID {
-			st.Records[i].Status = status
-			st.Records[i].LastChecked = time.Now()
-			return st.save()
-		}
+	_, err := st.TransitionScan(scanID, status)
+	return err
+}
+
+// UpdateScanURL replaces the URL of a scan, e.g. once a placeholder can be resolved
+func (st *ScanTracker) UpdateScanURL(scanID, url string) error {
+	return st.UpdateScan(scanID, func(record *ScanRecord) {
+		record.URL = url
+	})
+}
+
+// AnnotateScan applies tag, project and note changes to a scan
+func (st *ScanTracker) AnnotateScan(scanID string, annotation Annotation) (*ScanRecord, error) {
+	err := st.UpdateScan(scanID, annotation.Apply)
+	if err != nil {
+		return nil, err
 	}
-	return fmt.Errorf("scan ID %s not found", scanID)
+	return st.GetScanByID(scanID), nil
 }
 
-// GetAllScans returns all scan records
+// UpdateScan applies fn to a single record of the current session and marks
+// it as checked. Legacy records are claimed by the tracker's instance.
+func (st *ScanTracker) UpdateScan(scanID string, fn func(record *ScanRecord)) error {
+	session, err := st.Session()
+	if err != nil {
+		return err
+	}
+	record := st.GetScanByID(scanID)
+	if record == nil {
+		return fmt.Errorf("scan ID %s not found", scanID)
+	}
+
+	return st.storage.Update(record.Key(), func(record *ScanRecord) error {
+		fn(record)
+		record.LastChecked = time.Now()
+		if record.Instance == "" {
+			record.Instance = st.instance.Address()
+			record.KeyHint = st.instance.KeyHint
+			record.Session = session.Number
+		}
+		return nil
+	})
+}
+
+// GetAllScans returns all scan records of the instance, including unclaimed legacy records
 func (st *ScanTracker) GetAllScans() []ScanRecord {
-	return st.Records
+	owned, err := st.storage.ListByInstance(st.instance.ID())
+	if err != nil {
+		return nil
+	}
+	legacy, err := st.storage.ListByInstance("")
+	if err != nil {
+		return nil
+	}
+
+	records := append(owned, legacy...)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].StartTime.Before(records[j].StartTime)
+	})
+	return records
 }
 
-// GetScanByID returns a specific scan record
+// GetScansByStatus returns scans with the given status
+func (st *ScanTracker) GetScansByStatus(status string) ([]ScanRecord, error) {
+	records, err := st.storage.ListByStatus(status)
+	return st.scope(records), err
+}
+
+// GetScansByURL returns scans of the given target URL
+func (st *ScanTracker) GetScansByURL(url string) ([]ScanRecord, error) {
+	records, err := st.storage.ListByURL(url)
+	return st.scope(records), err
+}
+
+// GetScansBetween returns scans started in [from, to)
+func (st *ScanTracker) GetScansBetween(from, to time.Time) ([]ScanRecord, error) {
+	records, err := st.storage.ListBetween(from, to)
+	return st.scope(records), err
+}
+
+// QueryScans returns the page of records selected by q and the number of
+// matching records. Queries without an instance filter are scoped to the
+// tracker's instance; the storage indexes narrow the candidates when possible.
+func (st *ScanTracker) QueryScans(q ScanQuery) ([]ScanRecord, int, error) {
+	var records []ScanRecord
+	var err error
+	switch {
+	case q.Instance != "":
+		records, err = st.storage.List()
+	case !q.Since.IsZero() || !q.Until.IsZero():
+		records, err = st.GetScansBetween(q.Since, q.Until)
+	case len(q.Statuses) == 1:
+		records, err = st.GetScansByStatus(q.Statuses[0])
+	default:
+		records = st.GetAllScans()
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page, total := q.Apply(records)
+	return page, total, nil
+}
+
+// GetScanByID returns a copy of the scan record with the ID in the current
+// session of the instance, falling back to an unclaimed legacy record with
+// the same ID. Scans started before the last reset are not returned, as Burp
+// uses their IDs for other scans now.
 func (st *ScanTracker) GetScanByID(scanID string) *ScanRecord {
-	for i := range st.Records {
-		if st.Records[i].ScanID == scanID {
-			return &st.Records[i]
+	session, err := st.Session()
+	if err != nil {
+		return nil
+	}
+	owned := ScanRecord{ScanID: scanID, Instance: st.instance.Address(), Session: session.Number}
+	for _, key := range []string{owned.Key(), scanID} {
+		record, err := st.storage.Get(key)
+		if err == nil && record != nil && st.Current(*record, session) {
+			return record
 		}
 	}
 	return nil
 }
 
+// Watermark returns the highest scan ID synchronized from the instance and
+// whether one was stored yet
+func (st *ScanTracker) Watermark() (int, bool) {
+	value, err := st.storage.GetMeta(watermarkKey + st.instance.ID())
+	if err != nil || value == "" {
+		return 0, false
+	}
+	watermark, err := strconv.Atoi(value)
+	return watermark, err == nil
+}
+
+// SetWatermark stores the highest scan ID synchronized from the instance
+func (st *ScanTracker) SetWatermark(id int) error {
+	return st.storage.SetMeta(watermarkKey+st.instance.ID(), strconv.Itoa(id))
+}
+
 // RemoveScan removes a scan record
 func (st *ScanTracker) RemoveScan(scanID string) error {
-	for i := range st.Records {
-		if st.Records[i].ScanID == scanID {
-			st.Records = append(st.Records[:i], st.Records[i+1:]...)
-			return st.save()
-		}
+	record := st.GetScanByID(scanID)
+	if record == nil {
+		return fmt.Errorf("scan ID %s not found", scanID)
 	}
-	return fmt.Errorf("scan ID %s not found", scanID)
+	return st.storage.Remove(record.Key())
 }
 
 // ClearOldScans removes scans older than specified days
 func (st *ScanTracker) ClearOldScans(days int) error {
-	cutoffDate := time.Now().AddDate(0, 0, -days)
-	
-	var newRecords []ScanRecord
-	for _, record := range st.Records {
-		if record.StartTime.After(cutoffDate) {
-			newRecords = append(newRecords, record)
-		}
-	}
-	
-	st.Records = newRecords
-	return st.save()
-}
-
-// save writes the scan records to disk
-func (st *ScanTracker) save() error {
-	data, err := json.MarshalIndent(st, "", "  ")
+	expired, err := st.GetScansBetween(time.Time{}, time.Now().AddDate(0, 0, -days))
 	if err != nil {
-		return fmt.Errorf("failed to marshal scan records: %v", err)
+		return err
 	}
-	
-	if err := os.WriteFile(st.filePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write scan records: %v", err)
+	for _, record := range expired {
+		if err := st.storage.Remove(record.Key()); err != nil {
+			return err
+		}
 	}
-	
 	return nil
 }
 
-// load reads the scan records from disk
-func (st *ScanTracker) load() error {
-	data, err := os.ReadFile(st.filePath)
-	if err != nil {
-		return fmt.Errorf("failed to read scan records: %v", err)
-	}
-	
-	if err := json.Unmarshal(data, st); err != nil {
-		return fmt.Errorf("failed to unmarshal scan records: %v", err)
+// scope keeps the records owned by the instance and unclaimed legacy records
+func (st *ScanTracker) scope(records []ScanRecord) []ScanRecord {
+	var scoped []ScanRecord
+	for _, record := range records {
+		if record.Instance == "" || st.instance.Owns(record) {
+			scoped = append(scoped, record)
+		}
 	}
-	
-	return nil
+	return scoped
 }
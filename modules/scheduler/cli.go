@@ -2,6 +2,9 @@ package scheduler
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -22,6 +25,17 @@ var greenBG = color.New(color.Bold, color.BgGreen, color.FgBlack).SprintfFunc()
 // ScheduleCommand represents a schedule command
 type ScheduleCommand struct {
 	storage Storage
+	history HistoryStorage
+	
+	// Burp instance watched by the daemon
+	burpTarget string
+	burpPort   string
+	burpKey    string
+	sampler    MetricsSampler
+	
+	// Executor that launches the scans of due schedules
+	newExecutor ExecutorFactory
+	executor    Executor
 }
 
 // NewScheduleCLI creates a new schedule command handler (alias for compatibility)
@@ -42,11 +56,38 @@ func NewScheduleCommand() (*ScheduleCommand, error) {
 		return nil, fmt.Errorf("failed to initialize storage: %v", err)
 	}
 	
+	history, err := NewJSONHistoryStorage(filepath.Join(configDir, "schedule_history.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize execution history: %v", err)
+	}
+	
 	return &ScheduleCommand{
-		storage: storage,
+		storage:    storage,
+		history:    history,
+		burpTarget: "127.0.0.1",
+		burpPort:   "1337",
 	}, nil
 }
 
+// SetBurpInstance sets the default Burp instance of the daemon; -t, -p and -k
+// after "schedule daemon" override it
+func (sc *ScheduleCommand) SetBurpInstance(target, port, apikey string) {
+	sc.burpTarget = target
+	sc.burpPort = port
+	sc.burpKey = apikey
+}
+
+// SetMetricsSampler sets the function the daemon uses to sample running scans
+func (sc *ScheduleCommand) SetMetricsSampler(sampler MetricsSampler) {
+	sc.sampler = sampler
+}
+
+// SetExecutorFactory sets how the daemon creates the executor of due schedules
+func (sc *ScheduleCommand) SetExecutorFactory(factory ExecutorFactory) {
+	sc.newExecutor = factory
+	sc.executor = nil
+}
+
 // HandleScheduleCommand handles the main schedule command dispatch
 func (sc *ScheduleCommand) HandleScheduleCommand(args []string) error {
 	if len(args) == 0 {
@@ -67,6 +108,8 @@ func (sc *ScheduleCommand) HandleScheduleCommand(args []string) error {
 		return sc.HandleStatusCommand(subArgs)
 	case "test":
 		return sc.HandleTestCommand(subArgs)
+	case "history":
+		return sc.HandleHistoryCommand(subArgs)
 	case "daemon":
 		return sc.HandleDaemonCommand(subArgs)
 	case "help", "-h", "--help":
@@ -80,7 +123,7 @@ func (sc *ScheduleCommand) HandleScheduleCommand(args []string) error {
 // HandleCreateCommand handles schedule creation
 func (sc *ScheduleCommand) HandleCreateCommand(args []string) error {
 	if len(args) == 0 {
-		fmt.Fprintf(color.Output, "%v Schedule type required (daily, weekly, monthly)\n", red(" [-] ERROR:"))
+		fmt.Fprintf(color.Output, "%v Schedule type required (daily, weekly, monthly, cron, interval, once)\n", red(" [-] ERROR:"))
 		return sc.ShowCreateHelp()
 	}
 	
@@ -104,6 +147,7 @@ func (sc *ScheduleCommand) HandleCreateCommand(args []string) error {
 		Type:       scheduleType,
 		Pattern:    config.Pattern,
 		ScanConfig: config.ScanConfig,
+		Timezone:   config.Timezone,
 		CreatedAt:  time.Now(),
 		Enabled:    true,
 	}
@@ -125,7 +169,12 @@ func (sc *ScheduleCommand) HandleCreateCommand(args []string) error {
 	fmt.Fprintf(color.Output, "  • ID: %s\n", schedule.ID)
 	fmt.Fprintf(color.Output, "  • Name: %s\n", schedule.Name)
 	fmt.Fprintf(color.Output, "  • Type: %s\n", schedule.Type)
-	fmt.Fprintf(color.Output, "  • Next run: %s\n", schedule.NextRun.Format("2006-01-02 15:04:05 MST"))
+	fmt.Fprintf(color.Output, "  • Next run: %s\n", schedule.FormatTime(schedule.NextRun))
+	
+	// Cron expressions and intervals are easy to get wrong, so show where they lead
+	if schedule.Type == "cron" || schedule.Type == "interval" {
+		sc.printUpcomingRuns(schedule, 5)
+	}
 	
 	return nil
 }
@@ -157,11 +206,12 @@ func (sc *ScheduleCommand) HandleListCommand(args []string) error {
 		fmt.Fprintf(color.Output, "  • Pattern: %s\n", sc.formatPattern(schedule))
 		fmt.Fprintf(color.Output, "  • Target: %s\n", schedule.ScanConfig.Target)
 		fmt.Fprintf(color.Output, "  • Status: %s\n", status)
-		fmt.Fprintf(color.Output, "  • Next run: %s\n", schedule.NextRun.Format("2006-01-02 15:04:05 MST"))
+		fmt.Fprintf(color.Output, "  • Next run: %s\n", schedule.FormatTime(schedule.NextRun))
 		
 		if schedule.LastRun != nil {
-			fmt.Fprintf(color.Output, "  • Last run: %s\n", schedule.LastRun.Format("2006-01-02 15:04:05 MST"))
+			fmt.Fprintf(color.Output, "  • Last run: %s\n", schedule.FormatTime(*schedule.LastRun))
 		}
+		sc.printExecutionSummary(schedule)
 		
 		fmt.Fprintf(color.Output, "\n")
 	}
@@ -216,6 +266,9 @@ func (sc *ScheduleCommand) HandleDeleteCommand(args []string) error {
 	if err := sc.storage.DeleteSchedule(scheduleID); err != nil {
 		return fmt.Errorf("failed to delete schedule: %v", err)
 	}
+	if err := sc.history.DeleteRecords(scheduleID); err != nil {
+		fmt.Fprintf(color.Output, "%v Failed to delete execution history: %v\n", yellow(" [!] WARNING:"), err)
+	}
 	
 	fmt.Fprintf(color.Output, "%v Schedule deleted successfully\n", green(" [+] SUCCESS:"))
 	return nil
@@ -246,11 +299,11 @@ func (sc *ScheduleCommand) HandleStatusCommand(args []string) error {
 		}
 		
 		fmt.Fprintf(color.Output, "%v %s\n", greenBG(fmt.Sprintf(" %s ", schedule.ID)), schedule.Name)
-		fmt.Fprintf(color.Output, "  • Next run: %s\n", schedule.NextRun.Format("2006-01-02 15:04:05 MST"))
+		fmt.Fprintf(color.Output, "  • Next run: %s\n", schedule.FormatTime(schedule.NextRun))
 		fmt.Fprintf(color.Output, "  • Time until next run: %s\n", FormatDuration(timeUntil))
 		
 		if schedule.LastRun != nil {
-			fmt.Fprintf(color.Output, "  • Last run: %s\n", schedule.LastRun.Format("2006-01-02 15:04:05 MST"))
+			fmt.Fprintf(color.Output, "  • Last run: %s\n", schedule.FormatTime(*schedule.LastRun))
 		} else {
 			fmt.Fprintf(color.Output, "  • Last run: Never\n")
 		}
@@ -260,6 +313,7 @@ func (sc *ScheduleCommand) HandleStatusCommand(args []string) error {
 			status = "Disabled"
 		}
 		fmt.Fprintf(color.Output, "  • Status: %s\n", status)
+		sc.printExecutionSummary(schedule)
 		fmt.Fprintf(color.Output, "\n")
 	}
 	
@@ -276,13 +330,18 @@ func (sc *ScheduleCommand) ShowHelp() error {
 	fmt.Fprintf(color.Output, "  • burp-cli schedule status                   - Show schedule status\n")
 	fmt.Fprintf(color.Output, "  • burp-cli schedule delete <id>              - Delete a schedule\n")
 	fmt.Fprintf(color.Output, "  • burp-cli schedule test <id>                - Test a schedule (dry-run)\n")
+	fmt.Fprintf(color.Output, "  • burp-cli schedule history <id> [filters]   - Show past executions\n")
 	fmt.Fprintf(color.Output, "  • burp-cli schedule daemon [--foreground]    - Run scheduler daemon\n")
+	fmt.Fprintf(color.Output, "  • burp-cli schedule daemon stop|status       - Stop or check the daemon\n")
 	fmt.Fprintf(color.Output, "  • burp-cli schedule help                     - Show this help\n")
 	fmt.Fprintf(color.Output, "\n")
 	fmt.Fprintf(color.Output, "%v Schedule Types:\n", greenBG(" [*] TYPES:"))
-	fmt.Fprintf(color.Output, "  • daily   - Run every day at specified time\n")
-	fmt.Fprintf(color.Output, "  • weekly  - Run on specific days of the week\n")
-	fmt.Fprintf(color.Output, "  • monthly - Run on specific day of the month\n")
+	fmt.Fprintf(color.Output, "  • daily    - Run every day at specified time\n")
+	fmt.Fprintf(color.Output, "  • weekly   - Run on specific days of the week\n")
+	fmt.Fprintf(color.Output, "  • monthly  - Run on specific day of the month\n")
+	fmt.Fprintf(color.Output, "  • cron     - Run on a 5-field cron expression or macro (@daily, @weekly, ...)\n")
+	fmt.Fprintf(color.Output, "  • interval - Run every N minutes, hours or days from a start time\n")
+	fmt.Fprintf(color.Output, "  • once     - Run once at a date and time, then disable the schedule\n")
 	fmt.Fprintf(color.Output, "\n")
 	fmt.Fprintf(color.Output, "%v Examples:\n", greenBG(" [*] EXAMPLES:"))
 	fmt.Fprintf(color.Output, "  # Create daily schedule\n")
@@ -293,6 +352,15 @@ func (sc *ScheduleCommand) ShowHelp() error {
 	fmt.Fprintf(color.Output, "\n")
 	fmt.Fprintf(color.Output, "  # Create monthly schedule\n")
 	fmt.Fprintf(color.Output, "  burp-cli schedule create monthly --time 02:00 --day 1 --name \"Monthly Scan\" --nmap scan.xml\n")
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "  # Create cron schedule (every weekday at 02:30 and 14:30)\n")
+	fmt.Fprintf(color.Output, "  burp-cli schedule create cron --cron \"30 2,14 * * mon-fri\" --name \"Weekday Scan\" --url https://example.com\n")
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "  # Rescan staging every 6 hours\n")
+	fmt.Fprintf(color.Output, "  burp-cli schedule create interval --every 6h --name \"Staging Rescan\" --url https://staging.example.com\n")
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "  # Scan once during a change window\n")
+	fmt.Fprintf(color.Output, "  burp-cli schedule create once --at \"2026-11-01 23:00\" --name \"Change Window\" --url https://example.com\n")
 	
 	return nil
 }
@@ -302,7 +370,7 @@ func (sc *ScheduleCommand) ShowCreateHelp() error {
 	fmt.Fprintf(color.Output, "%v Schedule Creation Help:\n", cyan(" [i] INFO:"))
 	fmt.Fprintf(color.Output, "\n")
 	fmt.Fprintf(color.Output, "%v Required Parameters:\n", greenBG(" [*] REQUIRED:"))
-	fmt.Fprintf(color.Output, "  --time HH:MM        Time to run (24-hour format)\n")
+	fmt.Fprintf(color.Output, "  --time HH:MM        Time to run (24-hour format; daily, weekly and monthly)\n")
 	fmt.Fprintf(color.Output, "  --name \"Name\"       Schedule name\n")
 	fmt.Fprintf(color.Output, "\n")
 	fmt.Fprintf(color.Output, "%v Scan Target (choose one):\n", greenBG(" [*] TARGET:"))
@@ -311,9 +379,19 @@ func (sc *ScheduleCommand) ShowCreateHelp() error {
 	fmt.Fprintf(color.Output, "  --nmap FILE         Nmap XML file\n")
 	fmt.Fprintf(color.Output, "\n")
 	fmt.Fprintf(color.Output, "%v Schedule-Specific Parameters:\n", greenBG(" [*] SCHEDULE:"))
-	fmt.Fprintf(color.Output, "  Daily:   (no additional parameters)\n")
-	fmt.Fprintf(color.Output, "  Weekly:  --days mon,tue,wed,thu,fri,sat,sun\n")
-	fmt.Fprintf(color.Output, "  Monthly: --day N (1-31) or --day last\n")
+	fmt.Fprintf(color.Output, "  Daily:    (no additional parameters)\n")
+	fmt.Fprintf(color.Output, "  Weekly:   --days mon,tue,wed,thu,fri,sat,sun\n")
+	fmt.Fprintf(color.Output, "  Monthly:  --day N (1-31) or --day last\n")
+	fmt.Fprintf(color.Output, "  Cron:     --cron \"MIN HOUR DAY MONTH WEEKDAY\" or --cron @daily\n")
+	fmt.Fprintf(color.Output, "            Fields accept *, lists (1,15), ranges (mon-fri), steps (*/15),\n")
+	fmt.Fprintf(color.Output, "            L for the last day of the month and mon#1 for the first Monday\n")
+	fmt.Fprintf(color.Output, "            Macros: @yearly, @monthly, @weekly, @daily, @hourly\n")
+	fmt.Fprintf(color.Output, "  Interval: --every 30m|6h|2d [--start \"YYYY-MM-DD HH:MM\"] (default start: now)\n")
+	fmt.Fprintf(color.Output, "  Once:     --at \"YYYY-MM-DD HH:MM\" (the schedule is disabled after it ran)\n")
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "%v Time Zone:\n", greenBG(" [*] TIMEZONE:"))
+	fmt.Fprintf(color.Output, "  --timezone ZONE     IANA time zone of the times above, e.g. Europe/Berlin\n")
+	fmt.Fprintf(color.Output, "                      (default: the daemon's local time zone)\n")
 	fmt.Fprintf(color.Output, "\n")
 	fmt.Fprintf(color.Output, "%v Optional Scan Parameters:\n", greenBG(" [*] OPTIONAL:"))
 	fmt.Fprintf(color.Output, "  --config N          Burp configuration number\n")
@@ -332,6 +410,9 @@ func (sc *ScheduleCommand) parseCreateArgs(scheduleType string, args []string) (
 		ScanConfig: ScanConfig{},
 	}
 	
+	// --start and --at are read in the schedule's time zone once all arguments are known
+	startArg := ""
+	
 	// Parse arguments
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
@@ -373,6 +454,34 @@ func (sc *ScheduleCommand) parseCreateArgs(scheduleType string, args []string) (
 			}
 			i++
 			
+		case "--cron":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("--cron requires a value")
+			}
+			config.Pattern.Cron = args[i+1]
+			i++
+			
+		case "--every":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("--every requires a value")
+			}
+			config.Pattern.Interval = args[i+1]
+			i++
+			
+		case "--start", "--at":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s requires a value", arg)
+			}
+			startArg = args[i+1]
+			i++
+			
+		case "--timezone", "--tz":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s requires a value", arg)
+			}
+			config.Timezone = args[i+1]
+			i++
+			
 		case "--url":
 			if i+1 >= len(args) {
 				return nil, fmt.Errorf("--url requires a value")
@@ -448,9 +557,53 @@ func (sc *ScheduleCommand) parseCreateArgs(scheduleType string, args []string) (
 		}
 	}
 	
+	loc, err := (&Schedule{Timezone: config.Timezone}).Location()
+	if err != nil {
+		return nil, err
+	}
+	if startArg != "" {
+		start, err := ParseStartTime(startArg, loc)
+		if err != nil {
+			return nil, err
+		}
+		config.Pattern.Start = &start
+	}
+	
 	// Validate required fields
-	if config.Pattern.Time == "" {
-		return nil, fmt.Errorf("--time is required")
+	switch scheduleType {
+	case "cron":
+		if config.Pattern.Cron == "" {
+			return nil, fmt.Errorf("--cron is required for cron schedules")
+		}
+		if config.Pattern.Time != "" {
+			return nil, fmt.Errorf("--time cannot be used with cron schedules; put the time in the expression")
+		}
+	case "interval":
+		if config.Pattern.Interval == "" {
+			return nil, fmt.Errorf("--every is required for interval schedules")
+		}
+		if config.Pattern.Time != "" {
+			return nil, fmt.Errorf("--time cannot be used with interval schedules; use --start")
+		}
+		// Without --start the first run is one interval from now
+		if config.Pattern.Start == nil {
+			start := time.Now().Truncate(time.Minute)
+			config.Pattern.Start = &start
+		}
+	case "once":
+		if config.Pattern.Start == nil {
+			return nil, fmt.Errorf("--at is required for one-time schedules")
+		}
+		if config.Pattern.Time != "" {
+			return nil, fmt.Errorf("--time cannot be used with one-time schedules; use --at")
+		}
+		if !config.Pattern.Start.After(time.Now()) {
+			return nil, fmt.Errorf("--at must be in the future")
+		}
+	default:
+		if config.Pattern.Time == "" {
+			return nil, fmt.Errorf("--time is required")
+		}
 	}
 	
 	if config.Name == "" {
@@ -478,20 +631,46 @@ func (sc *ScheduleCommand) parseCreateArgs(scheduleType string, args []string) (
 
 // formatPattern formats a schedule pattern for display
 func (sc *ScheduleCommand) formatPattern(schedule *Schedule) string {
+	var pattern string
 	switch schedule.Type {
 	case "daily":
-		return fmt.Sprintf("Daily at %s", schedule.Pattern.Time)
+		pattern = fmt.Sprintf("Daily at %s", schedule.Pattern.Time)
 	case "weekly":
 		days := strings.Join(schedule.Pattern.Days, ", ")
-		return fmt.Sprintf("Weekly on %s at %s", days, schedule.Pattern.Time)
+		pattern = fmt.Sprintf("Weekly on %s at %s", days, schedule.Pattern.Time)
 	case "monthly":
 		if schedule.Pattern.DayOfMonth == -1 {
-			return fmt.Sprintf("Monthly on last day at %s", schedule.Pattern.Time)
+			pattern = fmt.Sprintf("Monthly on last day at %s", schedule.Pattern.Time)
 		} else {
-			return fmt.Sprintf("Monthly on day %d at %s", schedule.Pattern.DayOfMonth, schedule.Pattern.Time)
+			pattern = fmt.Sprintf("Monthly on day %d at %s", schedule.Pattern.DayOfMonth, schedule.Pattern.Time)
 		}
+	case "cron":
+		pattern = fmt.Sprintf("Cron %s", schedule.Pattern.Cron)
+	case "interval", "once":
+		loc, _ := schedule.Location()
+		pattern = schedule.Pattern.describeStart(schedule.Type, loc)
+	default:
+		return "Unknown pattern"
+	}
+	
+	if schedule.Timezone != "" {
+		pattern += " (" + schedule.Timezone + ")"
+	}
+	return pattern
+}
+
+// printUpcomingRuns previews the next execution times of a schedule
+func (sc *ScheduleCommand) printUpcomingRuns(schedule *Schedule, count int) {
+	times, err := GetNextExecutionTimes(schedule, count, time.Now())
+	if err != nil {
+		fmt.Fprintf(color.Output, "%v Failed to preview upcoming runs: %v\n", yellow(" [!] WARNING:"), err)
+		return
+	}
+	
+	fmt.Fprintf(color.Output, "  • Upcoming runs:\n")
+	for _, next := range times {
+		fmt.Fprintf(color.Output, "      %s\n", schedule.FormatTime(next))
 	}
-	return "Unknown pattern"
 }
 
 // CreateConfig holds configuration for creating a schedule
@@ -499,6 +678,7 @@ type CreateConfig struct {
 	Name       string
 	Pattern    Pattern
 	ScanConfig ScanConfig
+	Timezone   string
 }
 
 // HandleTestCommand handles schedule testing (dry-run)
@@ -556,7 +736,8 @@ func (sc *ScheduleCommand) HandleTestCommand(args []string) error {
 		return nil
 	}
 	
-	fmt.Fprintf(color.Output, "%v Next execution time: %s\n", green(" [+] SUCCESS:"), nextRun.Format("2006-01-02 15:04:05 MST"))
+	fmt.Fprintf(color.Output, "%v Next execution time: %s\n", green(" [+] SUCCESS:"), targetSchedule.FormatTime(nextRun))
+	sc.printUpcomingRuns(targetSchedule, 5)
 	
 	// Show what command would be executed
 	fmt.Fprintf(color.Output, "\n%v Command that would be executed:\n", greenBG(" [*] COMMAND:"))
@@ -600,17 +781,200 @@ func (sc *ScheduleCommand) HandleTestCommand(args []string) error {
 	return nil
 }
 
+// HandleHistoryCommand shows the recorded executions of a schedule, newest first
+func (sc *ScheduleCommand) HandleHistoryCommand(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintf(color.Output, "%v Schedule ID required\n", red(" [-] ERROR:"))
+		return sc.ShowHistoryHelp()
+	}
+	
+	scheduleID := args[0]
+	outcome := ""
+	limit := 20
+	var since time.Time
+	
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--failed", "--succeeded":
+			outcome = strings.TrimPrefix(arg, "--")
+		case "--since", "--limit", "-n":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a value", arg)
+			}
+			i++
+			if arg == "--since" {
+				parsed, err := parseSince(args[i], time.Now())
+				if err != nil {
+					return err
+				}
+				since = parsed
+				continue
+			}
+			value, err := strconv.Atoi(args[i])
+			if err != nil || value < 0 {
+				return fmt.Errorf("invalid limit: %s", args[i])
+			}
+			limit = value
+		case "--help", "-h":
+			return sc.ShowHistoryHelp()
+		default:
+			fmt.Fprintf(color.Output, "%v Unknown history option: %s\n", red(" [-] ERROR:"), arg)
+			return sc.ShowHistoryHelp()
+		}
+	}
+	
+	schedule, err := sc.storage.GetScheduleByID(scheduleID)
+	if err != nil {
+		fmt.Fprintf(color.Output, "%v Schedule not found: %s\n", red(" [-] ERROR:"), scheduleID)
+		return nil
+	}
+	
+	records, err := sc.history.GetRecords(schedule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load execution history: %v", err)
+	}
+	
+	var matches []*ExecutionRecord
+	for idx := len(records) - 1; idx >= 0; idx-- {
+		record := records[idx]
+		if (outcome == "failed" && record.Success) || (outcome == "succeeded" && !record.Success) {
+			continue
+		}
+		if !since.IsZero() && record.ExecutedAt.Before(since) {
+			continue
+		}
+		matches = append(matches, record)
+	}
+	
+	fmt.Fprintf(color.Output, "%v Execution history of %s (%s):\n", cyan(" [i] INFO:"), schedule.Name, schedule.ID)
+	sc.printExecutionSummary(schedule)
+	fmt.Fprintf(color.Output, "\n")
+	
+	if len(matches) == 0 {
+		fmt.Fprintf(color.Output, "%v No matching executions found\n", cyan(" [i] INFO:"))
+		return nil
+	}
+	
+	shown := matches
+	if limit > 0 && len(shown) > limit {
+		shown = shown[:limit]
+	}
+	for _, record := range shown {
+		if record.Success {
+			fmt.Fprintf(color.Output, "%v %s\n", green(" [+] SUCCEEDED:"), schedule.FormatTime(record.ExecutedAt))
+		} else {
+			fmt.Fprintf(color.Output, "%v %s\n", red(" [-] FAILED:"), schedule.FormatTime(record.ExecutedAt))
+		}
+		if len(record.ScanIDs) > 0 {
+			fmt.Fprintf(color.Output, "  • Scan IDs: %s\n", strings.Join(record.ScanIDs, ", "))
+		}
+		duration := FormatDuration(record.Duration)
+		if record.Duration < time.Second {
+			duration = record.Duration.Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(color.Output, "  • Duration: %s\n", duration)
+		if record.ResultsPath != "" {
+			fmt.Fprintf(color.Output, "  • Results: %s\n", record.ResultsPath)
+		}
+		if record.Error != "" {
+			fmt.Fprintf(color.Output, "  • Error: %s\n", record.Error)
+		}
+	}
+	
+	if len(shown) < len(matches) {
+		fmt.Fprintf(color.Output, "\n%v Showing %d of %d executions; use --limit 0 to show all\n", cyan(" [i] INFO:"), len(shown), len(matches))
+	}
+	return nil
+}
+
+// ShowHistoryHelp displays help for the history command
+func (sc *ScheduleCommand) ShowHistoryHelp() error {
+	fmt.Fprintf(color.Output, "%v Usage: burp-cli schedule history <schedule-id> [options]\n", cyan(" [i] INFO:"))
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "%v Options:\n", greenBG(" [*] OPTIONS:"))
+	fmt.Fprintf(color.Output, "  --failed            Only failed executions\n")
+	fmt.Fprintf(color.Output, "  --succeeded         Only successful executions\n")
+	fmt.Fprintf(color.Output, "  --since <when>      Executions on or after a date (YYYY-MM-DD) or age (e.g., 7d, 36h)\n")
+	fmt.Fprintf(color.Output, "  --limit, -n <N>     Show the N newest executions (default: 20, 0 for all)\n")
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "%v The last %d executions of every schedule are kept\n", cyan(" [i] INFO:"), maxHistoryRecords)
+	return nil
+}
+
+// printExecutionSummary prints the last outcome and success rate of a schedule
+func (sc *ScheduleCommand) printExecutionSummary(schedule *Schedule) {
+	status, err := sc.history.GetScheduleStatus(schedule)
+	if err != nil {
+		fmt.Fprintf(color.Output, "  • Executions: unknown (%v)\n", err)
+		return
+	}
+	if status.ExecutionCount == 0 {
+		fmt.Fprintf(color.Output, "  • Executions: none yet\n")
+		return
+	}
+	
+	last := status.LastExecution
+	if last.Success {
+		fmt.Fprintf(color.Output, "  • Last outcome: %s at %s\n", green("succeeded"), schedule.FormatTime(last.ExecutedAt))
+	} else {
+		fmt.Fprintf(color.Output, "  • Last outcome: %s at %s: %s\n", red("failed"), schedule.FormatTime(last.ExecutedAt), status.LastError)
+	}
+	fmt.Fprintf(color.Output, "  • Success rate: %.0f%% (%d of %d executions)\n", status.SuccessRate(), status.SuccessCount, status.ExecutionCount)
+}
+
+// parseSince parses a date (YYYY-MM-DD, "YYYY-MM-DD HH:MM" or RFC 3339) or an
+// age like 7d or 36h into the earliest time to include
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if age, err := ParseInterval(value); err == nil {
+		return now.Add(-age), nil
+	}
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return date, nil
+	}
+	if date, err := ParseStartTime(value, time.Local); err == nil {
+		return date, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (use YYYY-MM-DD or an age like 7d)", value)
+}
+
 // HandleDaemonCommand handles scheduler daemon operations
 func (sc *ScheduleCommand) HandleDaemonCommand(args []string) error {
 	foreground := false
 	
+	// v1.3.0: "daemon stop" and "daemon status" manage a running daemon
+	if len(args) > 0 {
+		switch args[0] {
+		case "stop":
+			return sc.HandleDaemonStop()
+		case "status":
+			return sc.HandleDaemonStatus()
+		case "start":
+			args = args[1:]
+		}
+	}
+	
 	// Parse daemon arguments
-	for _, arg := range args {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
 		switch arg {
 		case "--foreground", "-f":
 			foreground = true
 		case "--help", "-h":
 			return sc.ShowDaemonHelp()
+		case "-t", "--target", "-p", "--port", "-k", "--key":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a value", arg)
+			}
+			i++
+			switch arg {
+			case "-t", "--target":
+				sc.burpTarget = args[i]
+			case "-p", "--port":
+				sc.burpPort = args[i]
+			default:
+				sc.burpKey = args[i]
+			}
 		default:
 			fmt.Fprintf(color.Output, "%v Unknown daemon option: %s\n", red(" [-] ERROR:"), arg)
 			return sc.ShowDaemonHelp()
@@ -620,7 +984,7 @@ func (sc *ScheduleCommand) HandleDaemonCommand(args []string) error {
 	fmt.Fprintf(color.Output, "%v Starting Scheduler Daemon\n", cyan(" [i] INFO:"))
 	
 	if foreground {
-		fmt.Fprintf(color.Output, "%v Running in foreground mode (Ctrl+C to stop)\n", cyan(" [i] INFO:"))
+		fmt.Fprintf(color.Output, "%v Running in foreground mode\n", cyan(" [i] INFO:"))
 		return sc.runDaemonForeground()
 	} else {
 		fmt.Fprintf(color.Output, "%v Running in background mode\n", cyan(" [i] INFO:"))
@@ -633,10 +997,13 @@ func (sc *ScheduleCommand) ShowDaemonHelp() error {
 	fmt.Fprintf(color.Output, "%v Scheduler Daemon Help:\n", cyan(" [i] INFO:"))
 	fmt.Fprintf(color.Output, "\n")
 	fmt.Fprintf(color.Output, "%v Usage:\n", greenBG(" [*] USAGE:"))
-	fmt.Fprintf(color.Output, "  burp-cli schedule daemon [options]\n")
+	fmt.Fprintf(color.Output, "  burp-cli schedule daemon [start] [options]\n")
+	fmt.Fprintf(color.Output, "  burp-cli schedule daemon stop\n")
+	fmt.Fprintf(color.Output, "  burp-cli schedule daemon status\n")
 	fmt.Fprintf(color.Output, "\n")
 	fmt.Fprintf(color.Output, "%v Options:\n", greenBG(" [*] OPTIONS:"))
 	fmt.Fprintf(color.Output, "  --foreground, -f    Run in foreground (default: background)\n")
+	fmt.Fprintf(color.Output, "  -t, -p, -k          Burp address, port and API key (default: 127.0.0.1:1337)\n")
 	fmt.Fprintf(color.Output, "  --help, -h          Show this help\n")
 	fmt.Fprintf(color.Output, "\n")
 	fmt.Fprintf(color.Output, "%v Examples:\n", greenBG(" [*] EXAMPLES:"))
@@ -645,17 +1012,43 @@ func (sc *ScheduleCommand) ShowDaemonHelp() error {
 	fmt.Fprintf(color.Output, "\n")
 	fmt.Fprintf(color.Output, "  # Run daemon in foreground (for debugging)\n")
 	fmt.Fprintf(color.Output, "  burp-cli schedule daemon --foreground\n")
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "  # Check or stop the background daemon\n")
+	fmt.Fprintf(color.Output, "  burp-cli schedule daemon status\n")
+	fmt.Fprintf(color.Output, "  burp-cli schedule daemon stop\n")
+	fmt.Fprintf(color.Output, "\n")
+	fmt.Fprintf(color.Output, "%v Due schedules start their scans on the Burp instance given with -t, -p and -k\n", cyan(" [i] INFO:"))
+	fmt.Fprintf(color.Output, "%v While running, the daemon records metric samples of unfinished scans\n", cyan(" [i] INFO:"))
+	fmt.Fprintf(color.Output, "%v View them with: burp-cli scans metrics <id> --history\n", cyan(" [i] INFO:"))
+	fmt.Fprintf(color.Output, "%v SIGHUP reloads schedules.json; SIGTERM and SIGINT stop the daemon after running scans checkpoint\n", cyan(" [i] INFO:"))
 	
 	return nil
 }
 
-// runDaemonForeground runs the scheduler daemon in foreground mode
+// runDaemonForeground runs the scheduler daemon in foreground mode until it
+// receives SIGTERM or SIGINT
 func (sc *ScheduleCommand) runDaemonForeground() error {
-	fmt.Fprintf(color.Output, "%v Scheduler daemon started in foreground mode\n", green(" [+] SUCCESS:"))
-	fmt.Fprintf(color.Output, "%v Press Ctrl+C to stop the daemon\n", cyan(" [i] INFO:"))
+	pidPath, _, err := daemonPaths()
+	if err != nil {
+		return err
+	}
+	
+	// The locked PID file keeps a second daemon from running the same schedules
+	pidFile, err := AcquirePIDFile(pidPath)
+	if err != nil {
+		return err
+	}
+	defer pidFile.Release()
+	
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, daemonSignals...)
+	defer signal.Stop(signals)
+	
+	fmt.Fprintf(color.Output, "%v Scheduler daemon started (PID %d)\n", green(" [+] SUCCESS:"), os.Getpid())
+	fmt.Fprintf(color.Output, "%v Press Ctrl+C or run 'burp-cli schedule daemon stop' to stop the daemon\n", cyan(" [i] INFO:"))
 	
-	// Create a simple scheduler loop
-	ticker := time.NewTicker(1 * time.Minute) // Check every minute
+	// Check for due schedules at a fixed interval
+	ticker := time.NewTicker(DefaultSchedulerConfig().CheckInterval)
 	defer ticker.Stop()
 	
 	for {
@@ -664,15 +1057,211 @@ func (sc *ScheduleCommand) runDaemonForeground() error {
 			if err := sc.checkAndExecuteSchedules(); err != nil {
 				fmt.Fprintf(color.Output, "%v Scheduler error: %v\n", red(" [-] ERROR:"), err)
 			}
+			sc.sampleMetrics()
+			
+		case sig := <-signals:
+			if isReloadSignal(sig) {
+				sc.reloadSchedules()
+				continue
+			}
+			
+			fmt.Fprintf(color.Output, "%v Received %v, shutting down\n", cyan(" [i] INFO:"), sig)
+			sc.checkpoint()
+			fmt.Fprintf(color.Output, "%v Scheduler daemon stopped\n", green(" [+] SUCCESS:"))
+			return nil
 		}
 	}
 }
 
-// runDaemonBackground runs the scheduler daemon in background mode
+// reloadSchedules re-reads schedules.json and runs schedules that became due
+func (sc *ScheduleCommand) reloadSchedules() {
+	schedules, err := sc.storage.LoadSchedules()
+	if err != nil {
+		fmt.Fprintf(color.Output, "%v Failed to reload schedules: %v\n", red(" [-] ERROR:"), err)
+		return
+	}
+	
+	enabled := 0
+	for _, schedule := range schedules {
+		if err := schedule.Validate(); err != nil {
+			fmt.Fprintf(color.Output, "%v Schedule %s is invalid: %v\n", yellow(" [!] WARNING:"), schedule.ID, err)
+			continue
+		}
+		if schedule.Enabled {
+			enabled++
+		}
+	}
+	fmt.Fprintf(color.Output, "%v Reloaded %d schedules (%d enabled)\n", green(" [+] SUCCESS:"), len(schedules), enabled)
+	
+	if err := sc.checkAndExecuteSchedules(); err != nil {
+		fmt.Fprintf(color.Output, "%v Scheduler error: %v\n", red(" [-] ERROR:"), err)
+	}
+}
+
+// checkpoint lets the executor save the state of the scans it monitors and
+// records a last metric sample of every unfinished scan. The scans keep
+// running in Burp.
+func (sc *ScheduleCommand) checkpoint() {
+	if checkpointer, ok := sc.executor.(Checkpointer); ok {
+		fmt.Fprintf(color.Output, "%v Waiting for running scans to checkpoint...\n", cyan(" [i] INFO:"))
+		if err := checkpointer.Checkpoint(shutdownTimeout); err != nil {
+			fmt.Fprintf(color.Output, "%v %v\n", yellow(" [!] WARNING:"), err)
+		}
+	}
+	sc.sampleMetrics()
+}
+
+// sampleMetrics records metric samples of the scans running on the Burp instance
+func (sc *ScheduleCommand) sampleMetrics() {
+	if sc.sampler == nil {
+		return
+	}
+	
+	if _, err := sc.sampler(sc.burpTarget, sc.burpPort, sc.burpKey); err != nil {
+		fmt.Fprintf(color.Output, "%v Failed to sample scan metrics: %v\n", yellow(" [!] WARNING:"), err)
+	}
+}
+
+// runDaemonBackground starts the daemon as a detached process that logs to
+// scheduler.log
 func (sc *ScheduleCommand) runDaemonBackground() error {
-	fmt.Fprintf(color.Output, "%v Background daemon mode not fully implemented yet\n", yellow(" [!] WARNING:"))
-	fmt.Fprintf(color.Output, "%v Use --foreground mode for now\n", cyan(" [i] INFO:"))
-	fmt.Fprintf(color.Output, "%v Example: burp-cli schedule daemon --foreground\n", cyan(" [i] INFO:"))
+	pidPath, logPath, err := daemonPaths()
+	if err != nil {
+		return err
+	}
+	
+	state, err := ReadDaemonState(pidPath)
+	if err != nil {
+		return err
+	}
+	if state.Running {
+		return fmt.Errorf("scheduler daemon is already running (PID %d)", state.PID)
+	}
+	
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the burp-cli executable: %v", err)
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %v", err)
+	}
+	defer logFile.Close()
+	
+	args := []string{"schedule", "daemon", "--foreground", "-t", sc.burpTarget, "-p", sc.burpPort}
+	if sc.burpKey != "" {
+		args = append(args, "-k", sc.burpKey)
+	}
+	cmd := exec.Command(executable, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detachedProcess()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start daemon: %v", err)
+	}
+	
+	// Wait until the daemon holds its PID file, or report why it exited
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case <-exited:
+			return fmt.Errorf("daemon exited during startup; see %s", logPath)
+		case <-deadline:
+			return fmt.Errorf("daemon did not start within 5 seconds; see %s", logPath)
+		case <-time.After(200 * time.Millisecond):
+		}
+		
+		if state, err := ReadDaemonState(pidPath); err == nil && state.Running && state.PID == cmd.Process.Pid {
+			break
+		}
+	}
+	
+	fmt.Fprintf(color.Output, "%v Scheduler daemon started in background (PID %d)\n", green(" [+] SUCCESS:"), cmd.Process.Pid)
+	fmt.Fprintf(color.Output, "%v Log file: %s\n", cyan(" [i] INFO:"), logPath)
+	fmt.Fprintf(color.Output, "%v Stop it with: burp-cli schedule daemon stop\n", cyan(" [i] INFO:"))
+	return nil
+}
+
+// HandleDaemonStop stops the background daemon and waits until it exited
+func (sc *ScheduleCommand) HandleDaemonStop() error {
+	pidPath, _, err := daemonPaths()
+	if err != nil {
+		return err
+	}
+	
+	state, err := ReadDaemonState(pidPath)
+	if err != nil {
+		return err
+	}
+	if !state.Running {
+		if state.Stale {
+			os.Remove(pidPath)
+			fmt.Fprintf(color.Output, "%v Removed stale PID file of PID %d\n", cyan(" [i] INFO:"), state.PID)
+		}
+		fmt.Fprintf(color.Output, "%v Scheduler daemon is not running\n", cyan(" [i] INFO:"))
+		return nil
+	}
+	
+	fmt.Fprintf(color.Output, "%v Stopping scheduler daemon (PID %d)...\n", cyan(" [i] INFO:"), state.PID)
+	if err := terminateProcess(state.PID); err != nil {
+		return fmt.Errorf("failed to stop daemon: %v", err)
+	}
+	
+	if !waitForExit(pidPath, stopTimeout) {
+		fmt.Fprintf(color.Output, "%v Daemon is still shutting down after %v\n", yellow(" [!] WARNING:"), stopTimeout)
+		return nil
+	}
+	fmt.Fprintf(color.Output, "%v Scheduler daemon stopped\n", green(" [+] SUCCESS:"))
+	return nil
+}
+
+// HandleDaemonStatus shows whether the daemon runs and what it does next
+func (sc *ScheduleCommand) HandleDaemonStatus() error {
+	pidPath, logPath, err := daemonPaths()
+	if err != nil {
+		return err
+	}
+	
+	state, err := ReadDaemonState(pidPath)
+	if err != nil {
+		return err
+	}
+	
+	if state.Running {
+		fmt.Fprintf(color.Output, "%v Scheduler daemon is running\n", green(" [+] SUCCESS:"))
+		fmt.Fprintf(color.Output, "  • PID: %d\n", state.PID)
+		fmt.Fprintf(color.Output, "  • Running since: %s (%s)\n", state.Since.Format("2006-01-02 15:04:05 MST"), FormatDuration(time.Since(state.Since)))
+	} else {
+		fmt.Fprintf(color.Output, "%v Scheduler daemon is not running\n", yellow(" [!] WARNING:"))
+		if state.Stale {
+			fmt.Fprintf(color.Output, "  • Stale PID file of PID %d: %s\n", state.PID, pidPath)
+		}
+	}
+	fmt.Fprintf(color.Output, "  • PID file: %s\n", pidPath)
+	fmt.Fprintf(color.Output, "  • Log file: %s\n", logPath)
+	
+	schedules, err := sc.storage.LoadSchedules()
+	if err != nil {
+		return fmt.Errorf("failed to load schedules: %v", err)
+	}
+	
+	var next *Schedule
+	enabled := 0
+	for _, schedule := range schedules {
+		if !schedule.Enabled {
+			continue
+		}
+		enabled++
+		if next == nil || schedule.NextRun.Before(next.NextRun) {
+			next = schedule
+		}
+	}
+	fmt.Fprintf(color.Output, "  • Schedules: %d (%d enabled)\n", len(schedules), enabled)
+	if next != nil {
+		fmt.Fprintf(color.Output, "  • Next run: %s - %s\n", next.Name, next.FormatTime(next.NextRun))
+	}
 	
 	return nil
 }
@@ -707,6 +1296,16 @@ func (sc *ScheduleCommand) checkAndExecuteSchedules() error {
 			now := time.Now()
 			schedule.LastRun = &now
 			
+			// One-time schedules are done after their run
+			if schedule.Type == "once" {
+				schedule.Enabled = false
+				if err := sc.storage.UpdateSchedule(schedule); err != nil {
+					fmt.Fprintf(color.Output, "%v Failed to update schedule %s: %v\n", red(" [-] ERROR:"), schedule.Name, err)
+				}
+				fmt.Fprintf(color.Output, "%v One-time schedule %s is now disabled\n", cyan(" [i] INFO:"), schedule.Name)
+				continue
+			}
+			
 			nextRun, err := calc.CalculateNextRun(schedule, now)
 			if err != nil {
 				fmt.Fprintf(color.Output, "%v Failed to calculate next run for %s: %v\n", red(" [-] ERROR:"), schedule.Name, err)
@@ -720,25 +1319,35 @@ func (sc *ScheduleCommand) checkAndExecuteSchedules() error {
 				fmt.Fprintf(color.Output, "%v Failed to update schedule %s: %v\n", red(" [-] ERROR:"), schedule.Name, err)
 			}
 			
-			fmt.Fprintf(color.Output, "%v Next run for %s: %s\n", cyan(" [i] INFO:"), schedule.Name, nextRun.Format("2006-01-02 15:04:05 MST"))
+			fmt.Fprintf(color.Output, "%v Next run for %s: %s\n", cyan(" [i] INFO:"), schedule.Name, schedule.FormatTime(nextRun))
 		}
 	}
 	
 	return nil
 }
 
-// executeSchedule executes a single schedule (placeholder for actual implementation)
+// executeSchedule launches the scans of a schedule on the daemon's Burp instance
 func (sc *ScheduleCommand) executeSchedule(schedule *Schedule) error {
-	// This is a placeholder - in a full implementation, this would:
-	// 1. Build the appropriate burp-cli command
-	// 2. Execute it using the existing burp-cli scan functions
-	// 3. Handle the results and exports
-	
-	fmt.Fprintf(color.Output, "%v Schedule execution is a placeholder in this version\n", yellow(" [!] WARNING:"))
-	fmt.Fprintf(color.Output, "%v Would execute: %s scan on %s\n", cyan(" [i] INFO:"), schedule.Type, schedule.ScanConfig.Target)
+	if sc.newExecutor == nil {
+		return fmt.Errorf("no scan executor configured")
+	}
 	
-	// Simulate execution time
-	time.Sleep(2 * time.Second)
+	// One executor per daemon follows the scans it started
+	if sc.executor == nil {
+		sc.executor = sc.newExecutor(sc.burpTarget, sc.burpPort, sc.burpKey, sc.history)
+	}
 	
-	return nil
-}
\ No newline at end of file
+	// The executor records its executions; record runs it refused here
+	if err := sc.executor.ValidateSchedule(schedule); err != nil {
+		record := &ExecutionRecord{
+			ScheduleID: schedule.ID,
+			ExecutedAt: time.Now(),
+			Error:      err.Error(),
+		}
+		if err := sc.history.AddRecord(record); err != nil {
+			fmt.Fprintf(color.Output, "%v Failed to record execution: %v\n", yellow(" [!] WARNING:"), err)
+		}
+		return err
+	}
+	return sc.executor.ExecuteSchedule(schedule)
+}
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeExecutor counts the schedules it was asked to run
+type fakeExecutor struct {
+	runs int
+}
+
+func (e *fakeExecutor) ExecuteSchedule(schedule *Schedule) error {
+	e.runs++
+	return nil
+}
+
+func (e *fakeExecutor) ValidateSchedule(schedule *Schedule) error {
+	return nil
+}
+
+func (e *fakeExecutor) GetExecutionHistory(scheduleID string) ([]*ExecutionRecord, error) {
+	return nil, nil
+}
+
+func TestCreateOnceInPast(t *testing.T) {
+	sc := &ScheduleCommand{}
+	past := time.Now().Add(-time.Hour).Format("2006-01-02 15:04")
+	future := time.Now().Add(time.Hour).Format("2006-01-02 15:04")
+	
+	if _, err := sc.parseCreateArgs("once", []string{"--name", "window", "--url", "https://example.com", "--at", past}); err == nil {
+		t.Errorf("created a one-time schedule at %s, which has passed", past)
+	}
+	if _, err := sc.parseCreateArgs("once", []string{"--name", "window", "--url", "https://example.com", "--at", future}); err != nil {
+		t.Errorf("failed to create a one-time schedule at %s: %v", future, err)
+	}
+}
+
+func TestOnceDisabledAfterRun(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := NewJSONStorage(filepath.Join(dir, "schedules.json"))
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	history, err := NewJSONHistoryStorage(filepath.Join(dir, "schedule_history.json"))
+	if err != nil {
+		t.Fatalf("failed to create history: %v", err)
+	}
+	
+	due := time.Now().Add(-time.Minute)
+	schedule := &Schedule{
+		ID:         "sched_once",
+		Name:       "window",
+		Type:       "once",
+		Pattern:    Pattern{Start: &due},
+		ScanConfig: ScanConfig{ScanType: "url", Target: "https://example.com"},
+		CreatedAt:  due.Add(-time.Hour),
+		NextRun:    due,
+		Enabled:    true,
+	}
+	if err := storage.SaveSchedule(schedule); err != nil {
+		t.Fatalf("failed to save schedule: %v", err)
+	}
+	
+	executor := &fakeExecutor{}
+	sc := &ScheduleCommand{
+		storage: storage,
+		history: history,
+		newExecutor: func(target, port, apikey string, history HistoryStorage) Executor {
+			return executor
+		},
+	}
+	
+	// The second check must not run the schedule again
+	for check := 0; check < 2; check++ {
+		if err := sc.checkAndExecuteSchedules(); err != nil {
+			t.Fatalf("check %d failed: %v", check+1, err)
+		}
+	}
+	
+	if executor.runs != 1 {
+		t.Errorf("one-time schedule ran %d times, want 1", executor.runs)
+	}
+	stored, err := storage.GetScheduleByID(schedule.ID)
+	if err != nil {
+		t.Fatalf("failed to load schedule: %v", err)
+	}
+	if stored.Enabled {
+		t.Errorf("one-time schedule is still enabled after its run")
+	}
+	if stored.LastRun == nil {
+		t.Errorf("one-time schedule has no last run")
+	}
+}
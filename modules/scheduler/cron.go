@@ -19,6 +19,35 @@ func (c *CronCalculatorImpl) CalculateNextRun(schedule *Schedule, from time.Time
 		return time.Time{}, fmt.Errorf("invalid schedule: %v", err)
 	}
 	
+	// All calendar math happens in the schedule's time zone
+	loc, err := schedule.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	from = from.In(loc)
+	
+	if schedule.Type == "cron" {
+		cron, err := ParseCronExpression(schedule.Pattern.Cron)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return cron.Next(from)
+	}
+	
+	switch schedule.Type {
+	case "interval":
+		interval, err := ParseInterval(schedule.Pattern.Interval)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return c.calculateNextInterval(*schedule.Pattern.Start, interval, from), nil
+	case "once":
+		if !schedule.Pattern.Start.After(from) {
+			return time.Time{}, fmt.Errorf("one-time schedule has no run after %s", from.Format("2006-01-02 15:04"))
+		}
+		return schedule.Pattern.Start.In(loc), nil
+	}
+	
 	hour, minute, err := ParseTimeString(schedule.Pattern.Time)
 	if err != nil {
 		return time.Time{}, fmt.Errorf("invalid time format: %v", err)
@@ -76,51 +105,63 @@ func (c *CronCalculatorImpl) GetTimeUntilNext(schedule *Schedule, now time.Time)
 
 // calculateNextDaily calculates the next daily execution time
 func (c *CronCalculatorImpl) calculateNextDaily(hour, minute int, from time.Time) time.Time {
-	// Create target time for today
-	target := time.Date(from.Year(), from.Month(), from.Day(), hour, minute, 0, 0, from.Location())
+	// Step in calendar days; a day is not always 24 hours long
+	for day := 0; ; day++ {
+		target := wallClock(from.Year(), from.Month(), from.Day()+day, hour, minute, from.Location())
+		if target.After(from) {
+			return target
+		}
+	}
+}
+
+// calculateNextInterval returns the first run after from on the grid of
+// start plus a multiple of interval, so late checks do not shift later runs.
+// Whole-day intervals keep the wall-clock time of start across DST changes.
+func (c *CronCalculatorImpl) calculateNextInterval(start time.Time, interval time.Duration, from time.Time) time.Time {
+	start = start.In(from.Location())
+	if start.After(from) {
+		return start
+	}
 	
-	// If the target time has already passed today, schedule for tomorrow
-	if target.Before(from) || target.Equal(from) {
-		target = target.Add(24 * time.Hour)
+	periods := from.Sub(start) / interval
+	if interval%(24*time.Hour) != 0 {
+		return start.Add((periods + 1) * interval)
 	}
 	
-	return target
+	// A DST change moves the grid by at most an hour, so begin one period early
+	days := int(interval / (24 * time.Hour))
+	for period := max(int(periods)-1, 0); ; period++ {
+		target := wallClock(start.Year(), start.Month(), start.Day()+period*days, start.Hour(), start.Minute(), from.Location())
+		if target.After(from) {
+			return target
+		}
+	}
 }
 
 // calculateNextWeekly calculates the next weekly execution time
 func (c *CronCalculatorImpl) calculateNextWeekly(hour, minute int, days []string, from time.Time) (time.Time, error) {
 	// Convert day names to weekdays
-	targetWeekdays := make([]time.Weekday, 0, len(days))
+	targetWeekdays := make(map[time.Weekday]bool, len(days))
 	for _, day := range days {
 		weekday, err := GetWeekdayFromString(day)
 		if err != nil {
 			return time.Time{}, fmt.Errorf("invalid day name: %s", day)
 		}
-		targetWeekdays = append(targetWeekdays, weekday)
+		targetWeekdays[weekday] = true
 	}
 	
-	// Find the next occurrence
-	current := from
-	for i := 0; i < 8; i++ { // Check up to 8 days to ensure we find the next occurrence
-		// Create target time for current day
-		target := time.Date(current.Year(), current.Month(), current.Day(), hour, minute, 0, 0, current.Location())
-		
-		// Check if current day is one of the target weekdays
-		for _, targetWeekday := range targetWeekdays {
-			if current.Weekday() == targetWeekday {
-				// If it's today and the time hasn't passed yet, use today
-				if i == 0 && target.After(from) {
-					return target, nil
-				}
-				// If it's not today, or the time has passed, use this day
-				if i > 0 {
-					return target, nil
-				}
-			}
+	// Check up to 8 days to ensure we find the next occurrence
+	for day := 0; day < 8; day++ {
+		// Noon is never skipped by a DST change
+		date := time.Date(from.Year(), from.Month(), from.Day()+day, 12, 0, 0, 0, from.Location())
+		if !targetWeekdays[date.Weekday()] {
+			continue
 		}
 		
-		// Move to next day
-		current = current.Add(24 * time.Hour)
+		target := wallClock(date.Year(), date.Month(), date.Day(), hour, minute, from.Location())
+		if target.After(from) {
+			return target, nil
+		}
 	}
 	
 	return time.Time{}, fmt.Errorf("could not calculate next weekly execution time")
@@ -148,7 +189,7 @@ func (c *CronCalculatorImpl) calculateNextMonthly(hour, minute, dayOfMonth int,
 		}
 		
 		// Create target time for this month
-		target := time.Date(current.Year(), current.Month(), targetDay, hour, minute, 0, 0, current.Location())
+		target := wallClock(current.Year(), current.Month(), targetDay, hour, minute, current.Location())
 		
 		// If it's this month and the time hasn't passed yet, use this month
 		if i == 0 && target.After(from) {
@@ -167,6 +208,41 @@ func (c *CronCalculatorImpl) calculateNextMonthly(hour, minute, dayOfMonth int,
 	return time.Time{}, fmt.Errorf("could not calculate next monthly execution time")
 }
 
+// wallClock returns the first instant at which the clocks of loc show the
+// given date and time. A time repeated when the clocks go back resolves to
+// its first occurrence; a time skipped when they go forward moves forward by
+// the length of the gap, e.g. 02:30 becomes 03:30.
+func wallClock(year int, month time.Month, day, hour, minute int, loc *time.Location) time.Time {
+	wall := time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	
+	// The offsets in effect two days before and after cover any change that day
+	before := wall.AddDate(0, 0, -2)
+	var first time.Time
+	for _, probe := range []time.Time{before, wall.AddDate(0, 0, 2)} {
+		_, offset := probe.In(loc).Zone()
+		candidate := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if !sameWallClock(candidate, wall) {
+			continue
+		}
+		if first.IsZero() || candidate.Before(first) {
+			first = candidate
+		}
+	}
+	
+	if first.IsZero() {
+		// Skipped: keep the offset from before the change
+		_, offset := before.In(loc).Zone()
+		first = wall.Add(-time.Duration(offset) * time.Second).In(loc)
+	}
+	return first
+}
+
+// sameWallClock reports whether t shows the date, hour and minute of wall
+func sameWallClock(t, wall time.Time) bool {
+	return t.Year() == wall.Year() && t.Month() == wall.Month() && t.Day() == wall.Day() &&
+		t.Hour() == wall.Hour() && t.Minute() == wall.Minute()
+}
+
 // getLastDayOfMonth returns the last day of the given month
 func (c *CronCalculatorImpl) getLastDayOfMonth(year int, month time.Month) int {
 	// Get the first day of the next month, then subtract one day
@@ -187,6 +263,20 @@ func (c *CronCalculatorImpl) addMonth(t time.Time) time.Time {
 
 // ValidateScheduleTime validates that a schedule's time configuration is valid
 func ValidateScheduleTime(schedule *Schedule) error {
+	switch schedule.Type {
+	case "cron":
+		_, err := ParseCronExpression(schedule.Pattern.Cron)
+		return err
+	case "interval":
+		_, err := ParseInterval(schedule.Pattern.Interval)
+		return err
+	case "once":
+		if schedule.Pattern.Start == nil {
+			return fmt.Errorf("one-time schedules must specify a start time")
+		}
+		return nil
+	}
+	
 	hour, minute, err := ParseTimeString(schedule.Pattern.Time)
 	if err != nil {
 		return err
@@ -231,7 +321,12 @@ func GetNextExecutionTimes(schedule *Schedule, count int, from time.Time) ([]tim
 			return nil, err
 		}
 		times = append(times, next)
-		current = next.Add(time.Minute) // Move past this execution time
+		
+		// One-time schedules run only once
+		if schedule.Type == "once" {
+			break
+		}
+		current = next // The next run is always after current
 	}
 	
 	return times, nil
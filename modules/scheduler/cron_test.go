@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// In Europe/Berlin the clocks go from 02:00 to 03:00 on 2025-03-30 and from
+// 03:00 back to 02:00 on 2025-10-26
+func loadBerlin(t *testing.T) *time.Location {
+	t.Helper()
+	
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("failed to load Europe/Berlin: %v", err)
+	}
+	return loc
+}
+
+func TestWallClockDST(t *testing.T) {
+	berlin := loadBerlin(t)
+	tests := []struct {
+		name string
+		date time.Time
+		want string
+	}{
+		{"regular day", time.Date(2025, 3, 29, 2, 30, 0, 0, time.UTC), "2025-03-29T01:30:00Z"},
+		{"skipped on spring forward", time.Date(2025, 3, 30, 2, 30, 0, 0, time.UTC), "2025-03-30T01:30:00Z"},
+		{"repeated on fall back", time.Date(2025, 10, 26, 2, 30, 0, 0, time.UTC), "2025-10-26T00:30:00Z"},
+		{"after fall back", time.Date(2025, 10, 26, 3, 30, 0, 0, time.UTC), "2025-10-26T02:30:00Z"},
+	}
+	
+	for _, tt := range tests {
+		got := wallClock(tt.date.Year(), tt.date.Month(), tt.date.Day(), tt.date.Hour(), tt.date.Minute(), berlin)
+		if got.UTC().Format(time.RFC3339) != tt.want {
+			t.Errorf("%s: wallClock = %s (%s), want %s", tt.name, got.Format(time.RFC3339), got.UTC().Format(time.RFC3339), tt.want)
+		}
+	}
+	
+	if got := wallClock(2025, 3, 30, 2, 30, berlin).Format("15:04 MST"); got != "03:30 CEST" {
+		t.Errorf("02:30 on the spring forward day resolves to %s, want 03:30 CEST", got)
+	}
+}
+
+func TestCalculateNextDailyDST(t *testing.T) {
+	berlin := loadBerlin(t)
+	calc := &CronCalculatorImpl{}
+	tests := []struct {
+		name string
+		from time.Time
+		want []string
+	}{
+		{"spring forward", time.Date(2025, 3, 29, 12, 0, 0, 0, berlin), []string{
+			"2025-03-30 03:30 CEST",
+			"2025-03-31 02:30 CEST",
+		}},
+		// 02:30 happens twice on 2025-10-26 and runs at the first one only
+		{"fall back", time.Date(2025, 10, 25, 12, 0, 0, 0, berlin), []string{
+			"2025-10-26 02:30 CEST",
+			"2025-10-27 02:30 CET",
+		}},
+	}
+	
+	for _, tt := range tests {
+		from := tt.from
+		for _, want := range tt.want {
+			next := calc.calculateNextDaily(2, 30, from)
+			if got := next.Format("2006-01-02 15:04 MST"); got != want {
+				t.Errorf("%s: next run after %s is %s, want %s", tt.name, from.Format("2006-01-02 15:04 MST"), got, want)
+				break
+			}
+			from = next
+		}
+	}
+}
+
+func TestCalculateNextIntervalDST(t *testing.T) {
+	berlin := loadBerlin(t)
+	calc := &CronCalculatorImpl{}
+	tests := []struct {
+		name     string
+		start    time.Time
+		interval time.Duration
+		runs     int
+	}{
+		{"1d across spring forward", time.Date(2025, 3, 27, 9, 0, 0, 0, berlin), 24 * time.Hour, 6},
+		{"1d across fall back", time.Date(2025, 10, 23, 9, 0, 0, 0, berlin), 24 * time.Hour, 6},
+		{"6h across spring forward", time.Date(2025, 3, 29, 21, 0, 0, 0, berlin), 6 * time.Hour, 8},
+		{"6h across fall back", time.Date(2025, 10, 25, 21, 0, 0, 0, berlin), 6 * time.Hour, 8},
+	}
+	
+	for _, tt := range tests {
+		previous := tt.start
+		for run := 1; run <= tt.runs; run++ {
+			next := calc.calculateNextInterval(tt.start, tt.interval, previous)
+			if tt.interval%(24*time.Hour) == 0 {
+				// Whole days keep the wall-clock time of start
+				want := time.Date(tt.start.Year(), tt.start.Month(), tt.start.Day()+run, 9, 0, 0, 0, berlin)
+				if !next.Equal(want) || next.Hour() != 9 {
+					t.Errorf("%s: run %d is at %s, want %s", tt.name, run, next.Format("2006-01-02 15:04 MST"), want.Format("2006-01-02 15:04 MST"))
+				}
+			} else if elapsed := next.Sub(previous); elapsed != tt.interval {
+				// Shorter intervals are real time, whatever the clocks show
+				t.Errorf("%s: run %d is %s after the previous one at %s, want %s", tt.name, run, elapsed, next.Format("2006-01-02 15:04 MST"), tt.interval)
+			}
+			previous = next
+		}
+	}
+}
+
+// TestIntervalLateCheck lets the daemon check an hourly schedule late; the
+// runs after the late one must stay on the grid of the start time
+func TestIntervalLateCheck(t *testing.T) {
+	calc := NewCronCalculator()
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	schedule := &Schedule{
+		ID:         "sched_interval",
+		Name:       "hourly",
+		Type:       "interval",
+		Pattern:    Pattern{Interval: "1h", Start: &start},
+		Timezone:   "UTC",
+		ScanConfig: ScanConfig{ScanType: "url", Target: "https://example.com"},
+	}
+	
+	tests := []struct {
+		name    string
+		checked time.Time
+		want    time.Time
+	}{
+		{"on time", start, start.Add(time.Hour)},
+		{"minutes late", start.Add(time.Hour + 7*time.Minute), start.Add(2 * time.Hour)},
+		{"just before the next run", start.Add(2*time.Hour + 59*time.Minute), start.Add(3 * time.Hour)},
+		{"several runs missed", start.Add(5*time.Hour + 30*time.Minute), start.Add(6 * time.Hour)},
+	}
+	
+	for _, tt := range tests {
+		next, err := calc.CalculateNextRun(schedule, tt.checked)
+		if err != nil {
+			t.Errorf("%s: CalculateNextRun failed: %v", tt.name, err)
+			continue
+		}
+		if !next.Equal(tt.want) {
+			t.Errorf("%s: next run after a check at %s is %s, want %s", tt.name, tt.checked.Format("15:04"), next.Format("15:04"), tt.want.Format("15:04"))
+		}
+	}
+}
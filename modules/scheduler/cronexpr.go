@@ -0,0 +1,319 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros maps the supported macros to their 5-field expressions
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronMonthNames and cronDayNames are the names accepted in the month and
+// day-of-week fields
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+var cronDayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// cronSearchYears limits how far ahead Next looks for a matching time
+const cronSearchYears = 5
+
+// CronExpression is a parsed 5-field cron expression:
+// minute hour day-of-month month day-of-week
+type CronExpression struct {
+	source   string
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	
+	// lastDay matches the last day of the month ("L" in day-of-month)
+	lastDay bool
+	// nthWeekdays match the Nth weekday of the month ("mon#1" in day-of-week)
+	nthWeekdays []nthWeekday
+	
+	// Like cron, a day matches either day field when both are restricted
+	anyDay     bool
+	anyWeekday bool
+}
+
+// nthWeekday is an entry like "mon#1", the first Monday of the month
+type nthWeekday struct {
+	weekday time.Weekday
+	n       int
+}
+
+// cronField describes the value range of a cron field
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField  = cronField{"minute", 0, 59, nil}
+	hourField    = cronField{"hour", 0, 23, nil}
+	dayField     = cronField{"day-of-month", 1, 31, nil}
+	monthField   = cronField{"month", 1, 12, cronMonthNames}
+	weekdayField = cronField{"day-of-week", 0, 7, cronDayNames}
+)
+
+// ParseCronExpression parses a standard 5-field cron expression or a macro
+// such as @daily. Fields accept *, lists, ranges, steps and month and day
+// names; day-of-month also accepts L for the last day and day-of-week
+// entries like mon#1 for the first Monday of the month.
+func ParseCronExpression(expr string) (*CronExpression, error) {
+	expr = strings.TrimSpace(expr)
+	fields := strings.Fields(expr)
+	if strings.HasPrefix(expr, "@") {
+		macro, exists := cronMacros[strings.ToLower(expr)]
+		if !exists {
+			return nil, fmt.Errorf("unknown cron macro: %s", expr)
+		}
+		fields = strings.Fields(macro)
+	}
+	
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression must have 5 fields (minute hour day-of-month month day-of-week), got %d", len(fields))
+	}
+	
+	cron := &CronExpression{
+		source:     expr,
+		anyDay:     strings.HasPrefix(fields[2], "*"),
+		anyWeekday: strings.HasPrefix(fields[4], "*"),
+	}
+	
+	var err error
+	if cron.minutes, err = parseCronField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if cron.hours, err = parseCronField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if cron.months, err = parseCronField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	
+	// Day-of-month may contain L for the last day
+	var dayParts []string
+	for _, part := range strings.Split(fields[2], ",") {
+		if strings.EqualFold(part, "L") {
+			cron.lastDay = true
+			continue
+		}
+		dayParts = append(dayParts, part)
+	}
+	if len(dayParts) > 0 {
+		if cron.days, err = parseCronField(strings.Join(dayParts, ","), dayField); err != nil {
+			return nil, err
+		}
+	}
+	
+	// Day-of-week may contain entries like mon#1
+	var weekdayParts []string
+	for _, part := range strings.Split(fields[4], ",") {
+		if !strings.Contains(part, "#") {
+			weekdayParts = append(weekdayParts, part)
+			continue
+		}
+		nth, err := parseNthWeekday(part)
+		if err != nil {
+			return nil, err
+		}
+		cron.nthWeekdays = append(cron.nthWeekdays, nth)
+	}
+	if len(weekdayParts) > 0 {
+		if cron.weekdays, err = parseCronField(strings.Join(weekdayParts, ","), weekdayField); err != nil {
+			return nil, err
+		}
+		// 7 is Sunday as well
+		if cron.weekdays&(1<<7) != 0 {
+			cron.weekdays |= 1
+		}
+	}
+	
+	if !cron.matchesAnyMonth() {
+		return nil, fmt.Errorf("cron expression %q never matches", expr)
+	}
+	return cron, nil
+}
+
+// parseCronField parses one field into a bit set of the allowed values
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		if part == "" {
+			return 0, fmt.Errorf("empty value in %s field: %s", spec.name, field)
+		}
+		
+		rangePart, step := part, 1
+		if index := strings.Index(part, "/"); index >= 0 {
+			rangePart = part[:index]
+			value, err := strconv.Atoi(part[index+1:])
+			if err != nil || value < 1 {
+				return 0, fmt.Errorf("invalid step in %s field: %s", spec.name, part)
+			}
+			step = value
+		}
+		
+		low, high := spec.min, spec.max
+		switch {
+		case rangePart == "*":
+			if spec.name == weekdayField.name {
+				high = 6
+			}
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if low, err = parseCronValue(bounds[0], spec); err != nil {
+				return 0, err
+			}
+			if high, err = parseCronValue(bounds[1], spec); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range in %s field: %s", spec.name, part)
+			}
+		default:
+			value, err := parseCronValue(rangePart, spec)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			// "5/15" runs from 5 to the end of the range
+			if step == 1 {
+				high = value
+			}
+		}
+		
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+// parseCronValue parses a number or name within the range of a field
+func parseCronValue(value string, spec cronField) (int, error) {
+	if number, exists := spec.names[strings.ToLower(value)]; exists {
+		return number, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s field: %s", spec.name, value)
+	}
+	if number < spec.min || number > spec.max {
+		return 0, fmt.Errorf("%s must be between %d and %d, got %d", spec.name, spec.min, spec.max, number)
+	}
+	return number, nil
+}
+
+// parseNthWeekday parses a day-of-week entry like mon#1 or 1#1
+func parseNthWeekday(part string) (nthWeekday, error) {
+	bounds := strings.SplitN(part, "#", 2)
+	weekday, err := parseCronValue(bounds[0], weekdayField)
+	if err != nil {
+		return nthWeekday{}, err
+	}
+	n, err := strconv.Atoi(bounds[1])
+	if err != nil || n < 1 || n > 5 {
+		return nthWeekday{}, fmt.Errorf("invalid day-of-week entry %s: the number after # must be between 1 and 5", part)
+	}
+	return nthWeekday{weekday: time.Weekday(weekday % 7), n: n}, nil
+}
+
+// String returns the expression as it was given
+func (c *CronExpression) String() string {
+	return c.source
+}
+
+// Next returns the first matching time after from, in the location of from.
+// Matching wall-clock times resolve like wallClock: repeated times run once
+// and skipped times run after the clocks went forward.
+func (c *CronExpression) Next(from time.Time) (time.Time, error) {
+	loc := from.Location()
+	for day := 0; day <= cronSearchYears*366; day++ {
+		// Noon is never skipped by a DST change
+		date := time.Date(from.Year(), from.Month(), from.Day()+day, 12, 0, 0, 0, loc)
+		if c.months&(1<<uint(date.Month())) == 0 || !c.matchesDay(date) {
+			continue
+		}
+		
+		for hour := 0; hour < 24; hour++ {
+			if c.hours&(1<<uint(hour)) == 0 {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if c.minutes&(1<<uint(minute)) == 0 {
+					continue
+				}
+				if next := wallClock(date.Year(), date.Month(), date.Day(), hour, minute, loc); next.After(from) {
+					return next, nil
+				}
+			}
+		}
+	}
+	
+	return time.Time{}, fmt.Errorf("cron expression %q has no run in the next %d years", c.source, cronSearchYears)
+}
+
+// matchesDay reports whether the day of t matches the day fields
+func (c *CronExpression) matchesDay(t time.Time) bool {
+	dayMatch := c.days&(1<<uint(t.Day())) != 0 || (c.lastDay && t.Day() == lastDayOfMonth(t.Year(), t.Month()))
+	
+	weekdayMatch := c.weekdays&(1<<uint(t.Weekday())) != 0
+	for _, nth := range c.nthWeekdays {
+		if t.Weekday() == nth.weekday && (t.Day()-1)/7+1 == nth.n {
+			weekdayMatch = true
+		}
+	}
+	
+	switch {
+	case c.anyDay && c.anyWeekday:
+		return true
+	case c.anyDay:
+		return weekdayMatch
+	case c.anyWeekday:
+		return dayMatch
+	default:
+		return dayMatch || weekdayMatch
+	}
+}
+
+// matchesAnyMonth rejects day-of-month lists that no selected month has,
+// like "30 2" for February 30
+func (c *CronExpression) matchesAnyMonth() bool {
+	if !c.anyWeekday || c.anyDay || c.lastDay {
+		return true
+	}
+	for month := 1; month <= 12; month++ {
+		if c.months&(1<<uint(month)) == 0 {
+			continue
+		}
+		for day := 1; day <= lastDayOfMonth(2024, time.Month(month)); day++ {
+			if c.days&(1<<uint(day)) != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lastDayOfMonth returns the number of days in a month
+func lastDayOfMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
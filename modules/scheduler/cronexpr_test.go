@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronExpressionErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"@often",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"1,,2 * * * *",
+		"* * * foo *",
+		"0 0 * * mon#6",
+		"0 0 * * funday#1",
+		"0 0 30 2 *",
+	}
+	
+	for _, expr := range tests {
+		if _, err := ParseCronExpression(expr); err == nil {
+			t.Errorf("ParseCronExpression(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestCronExpressionNext(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		from string
+		// want lists the following runs, each computed from the previous one
+		want []string
+	}{
+		{"daily macro", "@daily", "2025-03-10 15:04", []string{"2025-03-11 00:00", "2025-03-12 00:00"}},
+		{"weekly macro", "@weekly", "2025-03-12 09:00", []string{"2025-03-16 00:00", "2025-03-23 00:00"}},
+		{"first monday", "0 2 * * mon#1", "2025-03-10 00:00", []string{"2025-04-07 02:00", "2025-05-05 02:00", "2025-06-02 02:00"}},
+		{"last day in a leap year", "0 0 L * *", "2024-02-10 00:00", []string{"2024-02-29 00:00", "2024-03-31 00:00"}},
+		{"last day in a common year", "0 0 L * *", "2025-02-10 00:00", []string{"2025-02-28 00:00", "2025-03-31 00:00"}},
+		{"steps in minute and hour", "*/15 */15 * * *", "2025-03-10 00:50", []string{"2025-03-10 15:00", "2025-03-10 15:15", "2025-03-10 15:30", "2025-03-10 15:45", "2025-03-11 00:00"}},
+		// Both day fields are restricted, so either one matches
+		{"day of month or weekday", "0 0 13 * 5", "2025-03-10 00:00", []string{"2025-03-13 00:00", "2025-03-14 00:00", "2025-03-21 00:00", "2025-03-28 00:00", "2025-04-04 00:00", "2025-04-11 00:00", "2025-04-13 00:00"}},
+	}
+	
+	for _, tt := range tests {
+		cron, err := ParseCronExpression(tt.expr)
+		if err != nil {
+			t.Errorf("%s: failed to parse %q: %v", tt.name, tt.expr, err)
+			continue
+		}
+		
+		from := mustParseTime(t, tt.from, time.UTC)
+		for _, want := range tt.want {
+			next, err := cron.Next(from)
+			if err != nil {
+				t.Errorf("%s: Next(%s) failed: %v", tt.name, from.Format("2006-01-02 15:04"), err)
+				break
+			}
+			if got := next.Format("2006-01-02 15:04"); got != want {
+				t.Errorf("%s: Next(%s) = %s, want %s", tt.name, from.Format("2006-01-02 15:04"), got, want)
+				break
+			}
+			from = next
+		}
+	}
+}
+
+// mustParseTime parses a "YYYY-MM-DD HH:MM" time in loc
+func mustParseTime(t *testing.T, value string, loc *time.Location) time.Time {
+	t.Helper()
+	
+	parsed, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", value, err)
+	}
+	return parsed
+}
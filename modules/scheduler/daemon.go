@@ -0,0 +1,149 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// shutdownTimeout is how long the daemon waits for running scans to checkpoint
+const shutdownTimeout = 30 * time.Second
+
+// stopTimeout is how long "daemon stop" waits for the daemon to exit
+const stopTimeout = 60 * time.Second
+
+// PIDFile is the locked PID file of a running daemon. The lock is held for
+// the lifetime of the daemon, so a stale file never blocks a new daemon.
+type PIDFile struct {
+	path string
+	file *os.File
+}
+
+// DaemonState describes the daemon as seen through its PID file
+type DaemonState struct {
+	PID     int
+	Running bool
+	// Since is when the daemon wrote its PID file
+	Since time.Time
+	// Stale is set when a PID file exists but no daemon holds its lock
+	Stale bool
+}
+
+// daemonPaths returns the expanded PID and log file paths
+func daemonPaths() (pidPath, logPath string, err error) {
+	config := DefaultSchedulerConfig()
+	if pidPath, err = ExpandPath(config.PIDPath); err != nil {
+		return "", "", err
+	}
+	if logPath, err = ExpandPath(config.LogPath); err != nil {
+		return "", "", err
+	}
+	return pidPath, logPath, nil
+}
+
+// AcquirePIDFile locks the PID file and writes the current PID to it. It
+// fails when another daemon holds the lock.
+func AcquirePIDFile(path string) (*PIDFile, error) {
+	if err := EnsureDirectoryExists(path); err != nil {
+		return nil, fmt.Errorf("failed to create PID file directory: %v", err)
+	}
+	
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PID file: %v", err)
+	}
+	if err := tryLockFile(file); err != nil {
+		file.Close()
+		if pid, readErr := readPID(path); readErr == nil {
+			return nil, fmt.Errorf("scheduler daemon is already running (PID %d)", pid)
+		}
+		return nil, fmt.Errorf("scheduler daemon is already running")
+	}
+	
+	if err := file.Truncate(0); err == nil {
+		_, err = file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if err != nil {
+		unlockFile(file)
+		file.Close()
+		return nil, fmt.Errorf("failed to write PID file: %v", err)
+	}
+	
+	return &PIDFile{path: path, file: file}, nil
+}
+
+// Release removes the PID file and drops its lock
+func (p *PIDFile) Release() error {
+	// Remove before unlocking so a new daemon never sees our PID
+	err := os.Remove(p.path)
+	unlockFile(p.file)
+	if closeErr := p.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// ReadDaemonState inspects the PID file. The daemon runs as long as it holds
+// the lock, which also covers PIDs reused by other processes.
+func ReadDaemonState(path string) (DaemonState, error) {
+	var state DaemonState
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to open PID file: %v", err)
+	}
+	defer file.Close()
+	
+	if info, err := file.Stat(); err == nil {
+		state.Since = info.ModTime()
+	}
+	state.PID, _ = readPID(path)
+	
+	if err := tryLockFile(file); err == nil {
+		unlockFile(file)
+		state.Stale = true
+		return state, nil
+	}
+	state.Running = processExists(state.PID)
+	return state, nil
+}
+
+// readPID reads the PID stored in a PID file
+func readPID(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	
+	data, err := io.ReadAll(io.LimitReader(file, 32))
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid PID file %s", path)
+	}
+	return pid, nil
+}
+
+// waitForExit polls the PID file until the daemon released it
+func waitForExit(path string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		state, err := ReadDaemonState(path)
+		if err == nil && !state.Running {
+			return true
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+//go:build !windows
+
+package scheduler
+
+import (
+	"os"
+	"syscall"
+)
+
+// daemonSignals are the signals the daemon handles
+var daemonSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP}
+
+// isReloadSignal reports whether sig asks the daemon to reload its schedules
+func isReloadSignal(sig os.Signal) bool {
+	return sig == syscall.SIGHUP
+}
+
+// detachedProcess starts the background daemon in a new session, without a
+// controlling terminal
+func detachedProcess() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// tryLockFile takes an exclusive flock on file without waiting
+func tryLockFile(file *os.File) error {
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// unlockFile releases the flock on file
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
+
+// processExists reports whether a process with the given PID exists. Signal 0
+// only checks for existence; EPERM means it belongs to another user.
+func processExists(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// terminateProcess asks a process to shut down gracefully
+func terminateProcess(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
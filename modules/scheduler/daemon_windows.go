@@ -0,0 +1,72 @@
+//go:build windows
+
+package scheduler
+
+import (
+	"os"
+	"syscall"
+	
+	"golang.org/x/sys/windows"
+)
+
+// daemonSignals are the signals the daemon handles; Windows has no SIGHUP
+var daemonSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// isReloadSignal reports whether sig asks the daemon to reload its schedules
+func isReloadSignal(sig os.Signal) bool {
+	return false
+}
+
+// detachedProcess starts the background daemon without a console
+func detachedProcess() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		CreationFlags: windows.DETACHED_PROCESS | windows.CREATE_NEW_PROCESS_GROUP,
+		HideWindow:    true,
+	}
+}
+
+// pidLockOffset locks a byte far beyond the PID so other processes can still
+// read the file
+const pidLockOffset = 1 << 30
+
+// tryLockFile takes an exclusive LockFileEx lock on file without waiting
+func tryLockFile(file *os.File) error {
+	flags := uint32(windows.LOCKFILE_EXCLUSIVE_LOCK | windows.LOCKFILE_FAIL_IMMEDIATELY)
+	return windows.LockFileEx(windows.Handle(file.Fd()), flags, 0, 1, 0, &windows.Overlapped{Offset: pidLockOffset})
+}
+
+// unlockFile releases the lock taken by tryLockFile
+func unlockFile(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, &windows.Overlapped{Offset: pidLockOffset})
+}
+
+// stillActive is the exit code of a process that has not exited
+const stillActive = 259
+
+// processExists reports whether a process with the given PID is running
+func processExists(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(handle)
+	
+	var code uint32
+	if err := windows.GetExitCodeProcess(handle, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// terminateProcess stops a process. Windows cannot deliver SIGTERM to a
+// detached process, so the daemon is killed without a checkpoint.
+func terminateProcess(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return process.Kill()
+}
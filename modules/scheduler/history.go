@@ -0,0 +1,273 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	
+	"burp-cli/modules/scanner"
+)
+
+// maxHistoryRecords is how many executions are kept per schedule. The
+// execution and success counts cover all executions.
+const maxHistoryRecords = 100
+
+// HistoryData represents the structure of the execution history file
+type HistoryData struct {
+	Schedules   map[string]*ScheduleHistory `json:"schedules"`
+	LastUpdated time.Time                   `json:"last_updated"`
+	Version     string                      `json:"version"`
+}
+
+// ScheduleHistory holds the executions of one schedule
+type ScheduleHistory struct {
+	ExecutionCount int                `json:"execution_count"`
+	SuccessCount   int                `json:"success_count"`
+	Records        []*ExecutionRecord `json:"records"` // Oldest first
+}
+
+// JSONHistoryStorage implements the HistoryStorage interface using a JSON file.
+// The daemon and CLI commands such as "schedule delete" share the file, so
+// every access also takes the sidecar lock used by the scan history.
+type JSONHistoryStorage struct {
+	filePath string
+	mutex    sync.RWMutex
+}
+
+// NewJSONHistoryStorage creates a new JSON execution history instance
+func NewJSONHistoryStorage(filePath string) (HistoryStorage, error) {
+	expandedPath, err := ExpandPath(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand path %s: %v", filePath, err)
+	}
+	
+	if err := EnsureDirectoryExists(expandedPath); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %v", err)
+	}
+	
+	return &JSONHistoryStorage{filePath: expandedPath}, nil
+}
+
+// AddRecord persists the record of an execution and assigns its ID
+func (h *JSONHistoryStorage) AddRecord(record *ExecutionRecord) error {
+	if record.ID == "" {
+		record.ID = GenerateExecutionID()
+	}
+	
+	return h.update(func(data *HistoryData) error {
+		history := data.Schedules[record.ScheduleID]
+		if history == nil {
+			history = &ScheduleHistory{}
+			data.Schedules[record.ScheduleID] = history
+		}
+		
+		history.ExecutionCount++
+		if record.Success {
+			history.SuccessCount++
+		}
+		history.Records = append(history.Records, record)
+		if len(history.Records) > maxHistoryRecords {
+			history.Records = history.Records[len(history.Records)-maxHistoryRecords:]
+		}
+		return nil
+	})
+}
+
+// UpdateRecord replaces the record of an execution, found by its ID. Records
+// written before executions had IDs are found by their execution time.
+func (h *JSONHistoryStorage) UpdateRecord(record *ExecutionRecord) error {
+	return h.update(func(data *HistoryData) error {
+		history := data.Schedules[record.ScheduleID]
+		if history == nil {
+			return fmt.Errorf("no execution history for schedule %s", record.ScheduleID)
+		}
+		
+		for idx, existing := range history.Records {
+			if !existing.matches(record) {
+				continue
+			}
+			
+			// Keep the success count in line with the updated outcome
+			if existing.Success && !record.Success {
+				history.SuccessCount--
+			} else if !existing.Success && record.Success {
+				history.SuccessCount++
+			}
+			history.Records[idx] = record
+			return nil
+		}
+		
+		return fmt.Errorf("execution of schedule %s at %s not found", record.ScheduleID, record.ExecutedAt.Format(time.RFC3339))
+	})
+}
+
+// matches reports whether two records describe the same execution
+func (r *ExecutionRecord) matches(other *ExecutionRecord) bool {
+	if r.ID != "" || other.ID != "" {
+		return r.ID == other.ID
+	}
+	return r.ExecutedAt.Equal(other.ExecutedAt)
+}
+
+// GetRecords returns the recorded executions of a schedule, oldest first
+func (h *JSONHistoryStorage) GetRecords(scheduleID string) ([]*ExecutionRecord, error) {
+	data, err := h.view()
+	if err != nil {
+		return nil, err
+	}
+	
+	history := data.Schedules[scheduleID]
+	if history == nil {
+		return []*ExecutionRecord{}, nil
+	}
+	return history.Records, nil
+}
+
+// GetScheduleStatus summarizes the executions of a schedule
+func (h *JSONHistoryStorage) GetScheduleStatus(schedule *Schedule) (*ScheduleStatus, error) {
+	data, err := h.view()
+	if err != nil {
+		return nil, err
+	}
+	
+	status := &ScheduleStatus{Schedule: schedule}
+	if schedule.Enabled {
+		status.NextRunIn = FormatDuration(time.Until(schedule.NextRun))
+	}
+	
+	history := data.Schedules[schedule.ID]
+	if history == nil || len(history.Records) == 0 {
+		return status, nil
+	}
+	
+	status.ExecutionCount = history.ExecutionCount
+	status.SuccessCount = history.SuccessCount
+	status.LastExecution = history.Records[len(history.Records)-1]
+	status.LastError = status.LastExecution.Error
+	return status, nil
+}
+
+// DeleteRecords removes the execution history of a schedule
+func (h *JSONHistoryStorage) DeleteRecords(scheduleID string) error {
+	return h.update(func(data *HistoryData) error {
+		if _, exists := data.Schedules[scheduleID]; !exists {
+			return errUnchanged
+		}
+		delete(data.Schedules, scheduleID)
+		return nil
+	})
+}
+
+// errUnchanged tells update that fn left the history as it was
+var errUnchanged = errors.New("history unchanged")
+
+// update rereads the history under the exclusive lock, applies fn and writes
+// the result, so the daemon and CLI commands never lose each other's changes
+func (h *JSONHistoryStorage) update(fn func(data *HistoryData) error) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	
+	unlock, err := h.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	
+	data, err := h.readData()
+	if err != nil {
+		return err
+	}
+	if err := fn(data); err != nil {
+		if err == errUnchanged {
+			return nil
+		}
+		return err
+	}
+	return h.writeData(data)
+}
+
+// view reads the history under the shared lock
+func (h *JSONHistoryStorage) view() (*HistoryData, error) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	
+	unlock, err := h.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	
+	return h.readData()
+}
+
+// lock takes the sidecar lock of the history file
+func (h *JSONHistoryStorage) lock(exclusive bool) (func(), error) {
+	unlock, err := scanner.LockSidecar(h.filePath, exclusive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock execution history: %v", err)
+	}
+	return unlock, nil
+}
+
+// readData reads and parses the history file
+func (h *JSONHistoryStorage) readData() (*HistoryData, error) {
+	historyData := &HistoryData{
+		Schedules: make(map[string]*ScheduleHistory),
+		Version:   "1.0",
+	}
+	if !FileExists(h.filePath) {
+		return historyData, nil
+	}
+	
+	data, err := os.ReadFile(h.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read execution history: %v", err)
+	}
+	
+	if err := json.Unmarshal(data, historyData); err != nil {
+		return nil, fmt.Errorf("failed to parse execution history: %v", err)
+	}
+	if historyData.Schedules == nil {
+		historyData.Schedules = make(map[string]*ScheduleHistory)
+	}
+	
+	return historyData, nil
+}
+
+// writeData writes the history file atomically
+func (h *JSONHistoryStorage) writeData(data *HistoryData) error {
+	data.LastUpdated = time.Now()
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+	
+	// Write to a temporary file of its own first so readers never see a
+	// partial file
+	tempFile, err := os.CreateTemp(filepath.Dir(h.filePath), filepath.Base(h.filePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	tempPath := tempFile.Name()
+	
+	if _, err := tempFile.Write(jsonData); err != nil {
+		tempFile.Close()
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	
+	if err := os.Rename(tempPath, h.filePath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to replace execution history: %v", err)
+	}
+	
+	return nil
+}
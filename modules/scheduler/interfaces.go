@@ -56,6 +56,24 @@ type Storage interface {
 	GetScheduleByName(name string) (*Schedule, error)
 }
 
+// HistoryStorage defines the interface for the persistent execution history
+type HistoryStorage interface {
+	// AddRecord persists the record of an execution and assigns its ID
+	AddRecord(record *ExecutionRecord) error
+	
+	// UpdateRecord replaces the record of an execution once its scans finished
+	UpdateRecord(record *ExecutionRecord) error
+	
+	// GetRecords returns the recorded executions of a schedule, oldest first
+	GetRecords(scheduleID string) ([]*ExecutionRecord, error)
+	
+	// GetScheduleStatus summarizes the executions of a schedule
+	GetScheduleStatus(schedule *Schedule) (*ScheduleStatus, error)
+	
+	// DeleteRecords removes the execution history of a schedule
+	DeleteRecords(scheduleID string) error
+}
+
 // Executor defines the interface for executing scheduled scans
 type Executor interface {
 	// ExecuteSchedule executes a scheduled scan
@@ -68,6 +86,21 @@ type Executor interface {
 	GetExecutionHistory(scheduleID string) ([]*ExecutionRecord, error)
 }
 
+// Checkpointer is implemented by executors that follow scans in the
+// background. Checkpoint saves the state of those scans before the daemon
+// exits and waits at most timeout; the scans keep running in Burp.
+type Checkpointer interface {
+	Checkpoint(timeout time.Duration) error
+}
+
+// ExecutorFactory creates the Executor that runs schedules on a Burp instance
+// and records their executions in history
+type ExecutorFactory func(target, port, apikey string, history HistoryStorage) Executor
+
+// MetricsSampler records a metric sample for every unfinished scan of a Burp
+// instance and returns the number of samples taken
+type MetricsSampler func(target, port, apikey string) (int, error)
+
 // CronCalculator defines the interface for time calculations
 type CronCalculator interface {
 	// CalculateNextRun calculates the next execution time for a schedule
@@ -100,12 +133,14 @@ type Logger interface {
 
 // ExecutionRecord represents a record of a schedule execution
 type ExecutionRecord struct {
+	// ID is assigned by AddRecord; records of older versions have none
+	ID          string    `json:"id,omitempty"`
 	ScheduleID  string    `json:"schedule_id"`
 	ExecutedAt  time.Time `json:"executed_at"`
 	Success     bool      `json:"success"`
 	Duration    time.Duration `json:"duration"`
 	Error       string    `json:"error,omitempty"`
-	ScanID      string    `json:"scan_id,omitempty"`
+	ScanIDs     []string  `json:"scan_ids,omitempty"`
 	ResultsPath string    `json:"results_path,omitempty"`
 }
 
@@ -5,14 +5,18 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	
+	// Embedded zone database for hosts without one, like Windows or slim containers
+	_ "time/tzdata"
 )
 
 // Schedule represents a scheduled scan configuration
 type Schedule struct {
 	ID         string     `json:"id"`
 	Name       string     `json:"name"`
-	Type       string     `json:"type"`        // "daily", "weekly", "monthly"
+	Type       string     `json:"type"`        // "daily", "weekly", "monthly", "cron", "interval", "once"
 	Pattern    Pattern    `json:"pattern"`
+	Timezone   string     `json:"timezone,omitempty"`  // IANA zone like "Europe/Berlin"; empty for the daemon's local zone
 	ScanConfig ScanConfig `json:"scan_config"`
 	CreatedAt  time.Time  `json:"created_at"`
 	LastRun    *time.Time `json:"last_run,omitempty"`
@@ -25,6 +29,9 @@ type Pattern struct {
 	Time       string   `json:"time"`                     // "HH:MM" format (24-hour)
 	Days       []string `json:"days,omitempty"`           // For weekly: ["Monday", "Friday"]
 	DayOfMonth int      `json:"day_of_month,omitempty"`   // For monthly: 1-31 or -1 for last day
+	Cron       string   `json:"cron,omitempty"`           // For cron: "30 2 * * 1-5" or a macro like "@daily"
+	Interval   string     `json:"interval,omitempty"`     // For interval: "6h", "90m" or "2d"
+	Start      *time.Time `json:"start,omitempty"`        // For interval: first run (anchor); for once: the run time
 }
 
 // ScanConfig contains all parameters needed to execute a scan
@@ -41,6 +48,16 @@ type ScheduleStatus struct {
 	LastError   string    `json:"last_error,omitempty"`
 	NextRunIn   string    `json:"next_run_in"`
 	ExecutionCount int    `json:"execution_count"`
+	SuccessCount   int    `json:"success_count"`
+	LastExecution  *ExecutionRecord `json:"last_execution,omitempty"`
+}
+
+// SuccessRate returns the percentage of successful executions
+func (s *ScheduleStatus) SuccessRate() float64 {
+	if s.ExecutionCount == 0 {
+		return 0
+	}
+	return float64(s.SuccessCount) * 100 / float64(s.ExecutionCount)
 }
 
 // Validate checks if the schedule configuration is valid
@@ -53,8 +70,12 @@ func (s *Schedule) Validate() error {
 		return fmt.Errorf("schedule name cannot be empty")
 	}
 	
-	if s.Type != "daily" && s.Type != "weekly" && s.Type != "monthly" {
-		return fmt.Errorf("schedule type must be 'daily', 'weekly', or 'monthly'")
+	if !IsValidScheduleType(s.Type) {
+		return fmt.Errorf("schedule type must be 'daily', 'weekly', 'monthly', 'cron', 'interval', or 'once'")
+	}
+	
+	if _, err := s.Location(); err != nil {
+		return err
 	}
 	
 	if err := s.Pattern.Validate(s.Type); err != nil {
@@ -70,6 +91,41 @@ func (s *Schedule) Validate() error {
 
 // Validate checks if the pattern is valid for the given schedule type
 func (p *Pattern) Validate(scheduleType string) error {
+	calendar := p.Time != "" || len(p.Days) > 0 || p.DayOfMonth != 0
+	
+	switch scheduleType {
+	case "cron":
+		// Cron schedules take their times from the expression
+		if calendar || p.Interval != "" || p.Start != nil {
+			return fmt.Errorf("cron schedules should only specify a cron expression")
+		}
+		_, err := ParseCronExpression(p.Cron)
+		return err
+		
+	case "interval":
+		if calendar || p.Cron != "" {
+			return fmt.Errorf("interval schedules should only specify an interval and a start time")
+		}
+		if p.Start == nil {
+			return fmt.Errorf("interval schedules must specify a start time")
+		}
+		_, err := ParseInterval(p.Interval)
+		return err
+		
+	case "once":
+		if calendar || p.Cron != "" || p.Interval != "" {
+			return fmt.Errorf("one-time schedules should only specify a start time")
+		}
+		if p.Start == nil {
+			return fmt.Errorf("one-time schedules must specify a start time")
+		}
+		return nil
+	}
+	
+	if p.Cron != "" || p.Interval != "" || p.Start != nil {
+		return fmt.Errorf("%s schedules only specify a time, days or a day of month", scheduleType)
+	}
+	
 	// Validate time format
 	if err := validateTimeFormat(p.Time); err != nil {
 		return err
@@ -236,7 +292,93 @@ func (s *Schedule) String() string {
 		} else {
 			pattern = fmt.Sprintf("Monthly on day %d at %s", s.Pattern.DayOfMonth, s.Pattern.Time)
 		}
+	case "cron":
+		pattern = fmt.Sprintf("Cron %s", s.Pattern.Cron)
+	case "interval", "once":
+		loc, _ := s.Location()
+		pattern = s.Pattern.describeStart(s.Type, loc)
+	}
+	if s.Timezone != "" {
+		pattern += " (" + s.Timezone + ")"
 	}
 	
 	return fmt.Sprintf("%s (%s): %s -> %s", s.Name, s.ID, pattern, s.ScanConfig.Target)
-}
\ No newline at end of file
+}
+
+// ParseInterval parses the interval of an interval schedule. It accepts Go
+// durations like "6h" or "1h30m" and whole days like "2d"; the minimum is
+// one minute, the daemon's check interval.
+func ParseInterval(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, fmt.Errorf("interval cannot be empty")
+	}
+	
+	var interval time.Duration
+	if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && strings.HasSuffix(value, "d") {
+		interval = time.Duration(days) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid interval %q (use e.g. 30m, 6h or 2d)", value)
+		}
+		interval = parsed
+	}
+	
+	if interval < time.Minute {
+		return 0, fmt.Errorf("interval must be at least 1m")
+	}
+	return interval, nil
+}
+
+// ParseStartTime parses the start time of an interval or one-time schedule,
+// given as "YYYY-MM-DD HH:MM" in the schedule's zone or in RFC 3339 format
+func ParseStartTime(value string, loc *time.Location) (time.Time, error) {
+	if start, err := time.ParseInLocation("2006-01-02 15:04", value, loc); err == nil {
+		// Resolve skipped and repeated times like the next-run calculation
+		return wallClock(start.Year(), start.Month(), start.Day(), start.Hour(), start.Minute(), loc), nil
+	}
+	if start, err := time.Parse(time.RFC3339, value); err == nil {
+		return start, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use \"YYYY-MM-DD HH:MM\")", value)
+}
+
+// describeStart describes the pattern of an interval or one-time schedule
+func (p *Pattern) describeStart(scheduleType string, loc *time.Location) string {
+	start := "?"
+	if p.Start != nil {
+		start = p.Start.In(loc).Format("2006-01-02 15:04 MST")
+	}
+	if scheduleType == "once" {
+		return fmt.Sprintf("Once at %s", start)
+	}
+	return fmt.Sprintf("Every %s from %s", p.Interval, start)
+}
+
+// Location returns the time zone of the schedule; the daemon's local zone
+// when none is set
+func (s *Schedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q (use an IANA name like Europe/Berlin)", s.Timezone)
+	}
+	return loc, nil
+}
+
+// FormatTime formats t in the schedule's time zone and adds the UTC time
+// when the zone is not UTC
+func (s *Schedule) FormatTime(t time.Time) string {
+	loc, err := s.Location()
+	if err != nil {
+		loc = time.Local
+	}
+	
+	local := t.In(loc)
+	if name, offset := local.Zone(); offset == 0 && name == "UTC" {
+		return local.Format("2006-01-02 15:04:05 MST")
+	}
+	return fmt.Sprintf("%s (%s)", local.Format("2006-01-02 15:04:05 MST"), t.UTC().Format("2006-01-02 15:04 UTC"))
+}
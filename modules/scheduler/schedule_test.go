@@ -0,0 +1,33 @@
+package scheduler
+
+import "testing"
+
+func TestParseStartTimeDST(t *testing.T) {
+	berlin := loadBerlin(t)
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"2025-03-29 02:30", "2025-03-29 02:30 CET"},
+		// Skipped when the clocks go forward
+		{"2025-03-30 02:30", "2025-03-30 03:30 CEST"},
+		// Repeated when the clocks go back; the first occurrence counts
+		{"2025-10-26 02:30", "2025-10-26 02:30 CEST"},
+		{"2025-03-30T02:30:00Z", "2025-03-30 02:30 UTC"},
+	}
+	
+	for _, tt := range tests {
+		start, err := ParseStartTime(tt.value, berlin)
+		if err != nil {
+			t.Errorf("ParseStartTime(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if got := start.Format("2006-01-02 15:04 MST"); got != tt.want {
+			t.Errorf("ParseStartTime(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+	
+	if _, err := ParseStartTime("2025-03-30 25:00", berlin); err == nil {
+		t.Errorf("ParseStartTime accepted an invalid time")
+	}
+}
@@ -17,6 +17,13 @@ func GenerateScheduleID() string {
 	return fmt.Sprintf("sched_%x", bytes)
 }
 
+// GenerateExecutionID generates a unique ID for an execution record
+func GenerateExecutionID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return fmt.Sprintf("exec_%x", bytes)
+}
+
 // ExpandPath expands ~ to the user's home directory
 func ExpandPath(path string) (string, error) {
 	if !strings.HasPrefix(path, "~") {
@@ -111,7 +118,7 @@ func GetWeekdayFromString(day string) (time.Weekday, error) {
 
 // IsValidScheduleType checks if a schedule type is valid
 func IsValidScheduleType(scheduleType string) bool {
-	validTypes := []string{"daily", "weekly", "monthly"}
+	validTypes := []string{"daily", "weekly", "monthly", "cron", "interval", "once"}
 	for _, validType := range validTypes {
 		if scheduleType == validType {
 			return true
@@ -172,14 +179,7 @@ func FileExists(path string) bool {
 
 // IsProcessRunning checks if a process with the given PID is running
 func IsProcessRunning(pid int) bool {
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-	
-	// On Unix systems, we can send signal 0 to check if process exists
-	err = process.Signal(os.Signal(nil))
-	return err == nil
+	return processExists(pid)
 }
 
 // GetCurrentTime returns the current time (useful for testing)